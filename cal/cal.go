@@ -26,19 +26,25 @@ import (
 	"github.com/emersion/go-ical"
 )
 
-// Load loads multiple calendars and returns an ordered series of events.
-func Load(paths ...string) event.Events {
-	es := []ical.Event{}
-	for _, p := range paths {
-		cal := decode(p)
-		es = append(es, cal.Events()...)
-	}
+// Source is a named calendar, which is read from a path or URL.
+type Source struct {
+	Name string
+	Path string
+}
 
-	eas := make([]event.Wrapper, len(es))
-	for i, e := range es {
-		e.Props.Get(ical.PropDateTimeStart).Params.Set(ical.ParamTimezoneID, "Local")
-		e.Props.Get(ical.PropDateTimeEnd).Params.Set(ical.ParamTimezoneID, "Local")
-		eas[i] = *event.NewCalEvent(e)
+// Load loads multiple calendars and returns an ordered series of events.
+// Each event remembers the name of its calendar.
+func Load(srcs ...Source) event.Events {
+	eas := []event.Wrapper{}
+	for _, src := range srcs {
+		cal := decode(src.Path)
+		for _, e := range cal.Events() {
+			e.Props.Get(ical.PropDateTimeStart).Params.Set(ical.ParamTimezoneID, "Local")
+			if end := e.Props.Get(ical.PropDateTimeEnd); end != nil {
+				end.Params.Set(ical.ParamTimezoneID, "Local")
+			}
+			eas = append(eas, *event.NewCalEvent(e, src.Name))
+		}
 	}
 
 	sort.SliceStable(eas, func(i, j int) bool {
@@ -0,0 +1,62 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cal_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/abc-inc/cal2cat/cal"
+	. "github.com/stretchr/testify/require"
+)
+
+const ics = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//cal2cat//test//EN
+BEGIN:VEVENT
+UID:1
+DTSTAMP:20210101T000000Z
+DTSTART;VALUE=DATE:20210101
+SUMMARY:New Year
+END:VEVENT
+BEGIN:VEVENT
+UID:2
+DTSTAMP:20210101T000000Z
+DTSTART:20210104T090000
+DTEND:20210104T103000
+SUMMARY:Meeting
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.ics")
+	NoError(t, os.WriteFile(path, []byte(strings.ReplaceAll(ics, "\n", "\r\n")), 0o600))
+
+	es := Load(Source{Name: "test", Path: path})
+	Len(t, es, 2)
+
+	Equal(t, "New Year", es[0].Summary())
+	Equal(t, time.Date(2021, 1, 1, 0, 0, 0, 0, time.Local), es[0].StartTime())
+	Equal(t, 24*time.Hour, es[0].Duration())
+
+	Equal(t, "Meeting", es[1].Summary())
+	Equal(t, "test", es[1].Calendar())
+	Equal(t, time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local), es[1].StartTime())
+	Equal(t, 90*time.Minute, es[1].Duration())
+}
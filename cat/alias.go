@@ -0,0 +1,63 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Canonical returns the canonical name of a category, i.e., the name the alias
+// refers to, or the name itself if it is not an alias.
+func (m Mapping) Canonical(cn string) string {
+	if c, ok := m.Aliases[cn]; ok {
+		return c
+	}
+	return cn
+}
+
+// SimilarNames returns groups of names, which differ only by case, whitespace
+// or punctuation, e.g., "Info Meeting" and "info-meeting". Each group is
+// sorted and the groups are ordered by their first name.
+func SimilarNames(cns []string) [][]string {
+	byKey := map[string][]string{}
+	for _, cn := range cns {
+		k := similarityKey(cn)
+		byKey[k] = append(byKey[k], cn)
+	}
+
+	gs := [][]string{}
+	for _, g := range byKey {
+		if len(g) > 1 {
+			sort.Strings(g)
+			gs = append(gs, g)
+		}
+	}
+	sort.Slice(gs, func(i, j int) bool {
+		return gs[i][0] < gs[j][0]
+	})
+	return gs
+}
+
+// similarityKey returns the lower-case letters and digits of a name.
+func similarityKey(cn string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, cn)
+}
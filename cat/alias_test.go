@@ -0,0 +1,52 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat_test
+
+import (
+	"testing"
+
+	. "github.com/abc-inc/cal2cat/cat"
+	"github.com/abc-inc/cal2cat/event"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestMapping_Map_Aliases(t *testing.T) {
+	rs := []Rule{}
+	for i, p := range [][2]string{
+		{"JF", "Info Meeting"}, {"All Staff", "Info-Meeting"}, {"CoP", "Infos"}, {"ABC", "Project ABC:50,Infos:50"},
+	} {
+		r, err := NewRule(p[0], p[1], i+1)
+		NoError(t, err)
+		rs = append(rs, r)
+	}
+	mp := Mapping{Rules: rs, Aliases: map[string]string{"Info-Meeting": "Info Meeting", "Infos": "Info Meeting"}}
+	Equal(t, []string{"Info Meeting", "Project ABC"}, mp.Categories())
+
+	cs := mp.Map(event.Events{newEvent("JF"), newEvent("All Staff"), newEvent("CoP"), newEvent("ABC")})
+	Equal(t, 2, len(cs))
+	Equal(t, "Info Meeting", cs[0].Name)
+	Equal(t, 4, len(cs[0].Events))
+	Equal(t, "Project ABC", cs[1].Name)
+
+	Equal(t, "Info Meeting", mp.Category(newEvent("CoP")))
+	Equal(t, "Project ABC", mp.Category(newEvent("ABC")))
+	Equal(t, "", mp.Category(newEvent("Lunch")))
+}
+
+func TestSimilarNames(t *testing.T) {
+	gs := SimilarNames([]string{"Info-Meeting", "Project ABC", "info meeting", "Infos", "project_abc", "Training"})
+	Equal(t, [][]string{{"Info-Meeting", "info meeting"}, {"Project ABC", "project_abc"}}, gs)
+	Empty(t, SimilarNames([]string{"A", "B"}))
+}
@@ -16,28 +16,156 @@ package cat
 
 import (
 	"sort"
+	"time"
 
 	"github.com/abc-inc/cal2cat/event"
 )
 
+// Uncategorized is the name of the category containing all events, for which
+// no category could be determined.
+const Uncategorized = "(uncategorized)"
+
 // Category is a named series of events.
 type Category struct {
 	Name   string
 	Events event.Events
+	// Uncategorized indicates that no rule matched any of the events.
+	Uncategorized bool
+	Metadata      Metadata
+}
+
+// Metadata describes how time spent on a category is booked and displayed.
+type Metadata struct {
+	// BookingCode identifies the category in the booking system, e.g., a cost
+	// center or a WBS element.
+	BookingCode string
+	Billable    bool
+	// Rate is the hourly rate of billable categories.
+	Rate        float64
+	Color       string
+	Description string
+	// Priority decides which of overlapping events is preferred.
+	Priority int
+}
+
+// Amount returns the billable amount for the given duration or zero, if the
+// category is not billable.
+func (md Metadata) Amount(d time.Duration) float64 {
+	if !md.Billable {
+		return 0
+	}
+	return d.Hours() * md.Rate
+}
+
+// Mapping categorizes events by their summary.
+type Mapping struct {
+	// Normalizer is applied to the summary before matching (optional).
+	Normalizer Normalizer
+	// Rules are tried in the given order until one of them matches.
+	Rules []Rule
+	// Classifier guesses the category if no rule matches (optional).
+	Classifier Classifier
+	// MinConfidence is the minimum confidence of a guess to be accepted.
+	MinConfidence float64
+	// Metadata is attached to the categories by name (optional).
+	Metadata map[string]Metadata
+	// Aliases maps alternative category names to canonical ones (optional).
+	Aliases map[string]string
 }
 
 // Map categorizes events using the given mapping.
+// Events not matching any Mapper are put into the category Uncategorized.
 func Map(es event.Events, ms []Mapper) []Category {
-	esByCatName := map[string]event.Events{}
-	for _, e := range es {
+	return group(es, func(e event.Wrapper) []assignment {
 		summary := e.Summary()
 		for _, m := range ms {
 			if n := m(summary); n != "" {
-				esByCatName[n] = append(esByCatName[n], e)
-				break
+				if as, err := ParseAllocations(n); err == nil {
+					return allocate(e, as)
+				}
+				return []assignment{{n, e}}
+			}
+		}
+		return nil
+	})
+}
+
+// Map categorizes events using the first matching Rule.
+// If no rule matches, the Classifier is consulted and the events it
+// categorizes are wrapped in a Guess.
+func (m Mapping) Map(es event.Events) []Category {
+	cs := group(es, func(e event.Wrapper) []assignment {
+		x := m.Explain(e)
+		switch {
+		case x.Match != nil:
+			as := allocate(e, x.Match.Allocations())
+			for i := range as {
+				as[i].category = m.Canonical(as[i].category)
+			}
+			return as
+		case x.Guess != "":
+			return []assignment{{m.Canonical(x.Guess), &Guess{e, x.Confidence}}}
+		}
+		return nil
+	})
+	for i := range cs {
+		cs[i].Metadata = m.Metadata[cs[i].Name]
+	}
+	return cs
+}
+
+// Category returns the canonical name of the (first) category of the event or
+// an empty string, if the event is uncategorized.
+func (m Mapping) Category(e event.Wrapper) string {
+	x := m.Explain(e)
+	switch {
+	case x.Match != nil:
+		return m.Canonical(x.Match.Allocations()[0].Category)
+	case x.Guess != "":
+		return m.Canonical(x.Guess)
+	}
+	return ""
+}
+
+// Categories returns the sorted canonical names of all categories of the
+// rules.
+func (m Mapping) Categories() []string {
+	seen := map[string]interface{}{}
+	cns := []string{}
+	for _, r := range m.Rules {
+		for _, a := range r.Allocations() {
+			cn := m.Canonical(a.Category)
+			if _, ok := seen[cn]; !ok {
+				seen[cn] = nil
+				cns = append(cns, cn)
 			}
 		}
 	}
+	sort.Strings(cns)
+	return cns
+}
+
+// assignment is an event (or a part of it) assigned to a category.
+type assignment struct {
+	category string
+	event    event.Wrapper
+}
+
+// group puts events into categories by name. The categories are sorted by
+// name, followed by the events without assignments, if any. The function
+// assign may wrap the event to attach additional information.
+func group(es event.Events, assign func(e event.Wrapper) []assignment) []Category {
+	esByCatName := map[string]event.Events{}
+	unmatched := event.Events{}
+	for _, e := range es {
+		as := assign(e)
+		for _, a := range as {
+			esByCatName[a.category] = append(esByCatName[a.category], a.event)
+		}
+		if len(as) == 0 {
+			unmatched = append(unmatched, e)
+		}
+	}
 
 	cns := []string{}
 	for cn := range esByCatName {
@@ -45,9 +173,20 @@ func Map(es event.Events, ms []Mapper) []Category {
 	}
 	sort.Strings(cns)
 
-	cs := make([]Category, len(cns))
+	cs := make([]Category, len(cns), len(cns)+1)
 	for i, cn := range cns {
-		cs[i] = Category{cn, esByCatName[cn]}
+		cs[i] = Category{Name: cn, Events: esByCatName[cn]}
+	}
+	if len(unmatched) > 0 {
+		cs = append(cs, Category{Name: Uncategorized, Events: unmatched, Uncategorized: true})
 	}
 	return cs
 }
+
+// normalize applies the Normalizer, if any.
+func (m Mapping) normalize(s string) string {
+	if m.Normalizer == nil {
+		return s
+	}
+	return m.Normalizer(s)
+}
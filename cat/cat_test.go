@@ -0,0 +1,184 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/abc-inc/cal2cat/cat"
+	"github.com/abc-inc/cal2cat/event"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestMapping_Map(t *testing.T) {
+	today := time.Now().Truncate(24 * time.Hour)
+	es := event.Events{
+		event.NewSimpleEvent(today, today.Add(time.Hour), "Canceled: ABC Review"),
+		event.NewSimpleEvent(today, today.Add(time.Hour), "XYZ"),
+	}
+	m, err := NewMapper("ABC", "Project ABC")
+	NoError(t, err)
+	r, err := NewRule("ABC", "Project ABC", 1)
+	NoError(t, err)
+
+	cs := Map(es, []Mapper{m})
+	Equal(t, 1, len(cs))
+	Equal(t, Uncategorized, cs[0].Name)
+	True(t, cs[0].Uncategorized)
+	Equal(t, 2, len(cs[0].Events))
+
+	cs = Mapping{Normalizer: NewPrefixStripper("Canceled:"), Rules: []Rule{r}}.Map(es)
+	Equal(t, 2, len(cs))
+	Equal(t, "Project ABC", cs[0].Name)
+	False(t, cs[0].Uncategorized)
+	Equal(t, "Canceled: ABC Review", cs[0].Events[0].Summary())
+	Equal(t, Uncategorized, cs[1].Name)
+	Equal(t, "XYZ", cs[1].Events[0].Summary())
+}
+
+func TestMapping_Explain(t *testing.T) {
+	rs := []Rule{}
+	for i, p := range []string{"*All Staff*", "^(ABC|ABD)", "ABD", "^"} {
+		r, err := NewRule(p, "C"+p, i+1)
+		NoError(t, err)
+		rs = append(rs, r)
+	}
+	m := Mapping{Normalizer: CollapseWhitespace, Rules: rs}
+
+	x := m.Explain(newEvent("  ABD  Review "))
+	Equal(t, "ABD Review", x.Normalized)
+	Equal(t, 1, len(x.Tried))
+	Equal(t, "*All Staff*", x.Tried[0].Pattern)
+	Equal(t, 2, x.Match.Line)
+	Equal(t, "^(ABC|ABD)=C^(ABC|ABD)", x.Match.String())
+
+	x = Mapping{Rules: rs[:1]}.Explain(newEvent("Review"))
+	Nil(t, x.Match)
+	Equal(t, 1, len(x.Tried))
+}
+
+func TestMapping_Map_Classifier(t *testing.T) {
+	nb := NewNaiveBayes()
+	nb.Train("ABC Review", "Project ABC")
+	nb.Train("ABC Kickoff", "Project ABC")
+	nb.Train("Security Training", "Training")
+
+	today := time.Now().Truncate(24 * time.Hour)
+	es := event.Events{
+		event.NewSimpleEvent(today, today.Add(time.Hour), "ABC Testing"),
+		event.NewSimpleEvent(today, today.Add(time.Hour), "Lunch"),
+	}
+	cs := Mapping{Classifier: nb, MinConfidence: 0.6}.Map(es)
+	Equal(t, 2, len(cs))
+	Equal(t, "Project ABC", cs[0].Name)
+	g, ok := cs[0].Events[0].(*Guess)
+	True(t, ok)
+	Greater(t, g.Confidence, 0.6)
+	Equal(t, "ABC Testing", g.Summary())
+	Equal(t, Uncategorized, cs[1].Name)
+}
+
+func TestMapping_Map_Metadata(t *testing.T) {
+	r, err := NewRule("ABC", "Project ABC", 1)
+	NoError(t, err)
+	md := Metadata{BookingCode: "4711", Billable: true, Rate: 90}
+	mp := Mapping{Rules: []Rule{r}, Metadata: map[string]Metadata{"Project ABC": md}}
+
+	cs := mp.Map(event.Events{newEvent("ABC Review"), newEvent("Lunch")})
+	Equal(t, 2, len(cs))
+	Equal(t, md, cs[0].Metadata)
+	Equal(t, 90.0, cs[0].Metadata.Amount(cs[0].Events.Duration()))
+	Equal(t, Metadata{}, cs[1].Metadata)
+	Equal(t, 0.0, cs[1].Metadata.Amount(time.Hour))
+}
+
+// newEvent creates a one hour event starting today at 08:00.
+func newEvent(summary string) event.Wrapper {
+	start := time.Now().Truncate(24 * time.Hour).Add(8 * time.Hour)
+	return event.NewSimpleEvent(start, start.Add(time.Hour), summary)
+}
+
+// calEvent is an event belonging to a calendar.
+type calEvent struct {
+	event.Wrapper
+	calendar string
+}
+
+func (e calEvent) Calendar() string {
+	return e.calendar
+}
+
+func TestMapping_Map_Calendar(t *testing.T) {
+	r1, err := NewRule("*Workshop*", "Training", 1)
+	NoError(t, err)
+	r1.Calendar = "customer"
+	r2, err := NewRule("*", "Project XYZ", 2)
+	NoError(t, err)
+	r2.Calendar = "customer"
+	r3, err := NewRule("ABC", "Project ABC", 3)
+	NoError(t, err)
+	m := Mapping{Rules: []Rule{r1, r2, r3}}
+
+	cs := m.Map(event.Events{
+		calEvent{newEvent("ABC Workshop"), "customer"},
+		calEvent{newEvent("ABC"), "customer"},
+		calEvent{newEvent("ABC"), "work"},
+		calEvent{newEvent("Workshop"), "work"},
+	})
+	Equal(t, 4, len(cs))
+	Equal(t, "Project ABC", cs[0].Name)
+	Equal(t, "work", cs[0].Events[0].Calendar())
+	Equal(t, "Project XYZ", cs[1].Name)
+	Equal(t, "Training", cs[2].Name)
+	Equal(t, "customer", cs[2].Events[0].Calendar())
+	Equal(t, Uncategorized, cs[3].Name)
+
+	x := m.Explain(calEvent{newEvent("Workshop"), "work"})
+	Equal(t, 1, len(x.Tried))
+	Equal(t, 3, x.Tried[0].Line)
+}
+
+func TestMapping_Map_Validity(t *testing.T) {
+	from, until, err := ParseDateRange("2021-04-01..")
+	NoError(t, err)
+	True(t, until.IsZero())
+	r1, err := NewRule("ABC", "Project ABC-2", 1)
+	NoError(t, err)
+	r1.ValidFrom, r1.ValidUntil = from, until
+
+	from, until, err = ParseDateRange("..2021-03-31")
+	NoError(t, err)
+	True(t, from.IsZero())
+	r2, err := NewRule("ABC", "Project ABC-1", 2)
+	NoError(t, err)
+	r2.ValidFrom, r2.ValidUntil = from, until
+
+	m := Mapping{Rules: []Rule{r1, r2}}
+	march := time.Date(2021, 3, 31, 23, 0, 0, 0, time.Local)
+	april := time.Date(2021, 4, 1, 0, 0, 0, 0, time.Local)
+	cs := m.Map(event.Events{
+		event.NewSimpleEvent(march, march.Add(time.Hour), "ABC"),
+		event.NewSimpleEvent(april, april.Add(time.Hour), "ABC"),
+	})
+	Equal(t, 2, len(cs))
+	Equal(t, "Project ABC-1", cs[0].Name)
+	Equal(t, march, cs[0].Events[0].StartTime())
+	Equal(t, "Project ABC-2", cs[1].Name)
+	Equal(t, april, cs[1].Events[0].StartTime())
+
+	_, _, err = ParseDateRange("2021-04-01")
+	Error(t, err)
+}
@@ -0,0 +1,127 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/abc-inc/cal2cat/event"
+)
+
+// Classifier guesses the category of a (normalized) summary.
+type Classifier interface {
+	// Classify returns the most likely category and the confidence in the
+	// range [0, 1], or an empty string if it cannot guess at all.
+	Classify(summary string) (string, float64)
+}
+
+// Guess is an event, whose category was guessed by a Classifier.
+type Guess struct {
+	event.Wrapper
+	Confidence float64
+}
+
+// Unwrap returns the event without the guess.
+func (g Guess) Unwrap() event.Wrapper {
+	return g.Wrapper
+}
+
+// NaiveBayes is a token-based naive Bayes classifier.
+type NaiveBayes struct {
+	samples     int
+	docs        map[string]int
+	tokens      map[string]map[string]int
+	tokensTotal map[string]int
+	vocabulary  map[string]interface{}
+}
+
+// NewNaiveBayes creates a new, untrained naive Bayes classifier.
+func NewNaiveBayes() *NaiveBayes {
+	return &NaiveBayes{
+		docs:        map[string]int{},
+		tokens:      map[string]map[string]int{},
+		tokensTotal: map[string]int{},
+		vocabulary:  map[string]interface{}{},
+	}
+}
+
+// Train adds a categorized summary to the training data.
+func (nb *NaiveBayes) Train(summary, category string) {
+	if category == "" {
+		return
+	}
+	if nb.tokens[category] == nil {
+		nb.tokens[category] = map[string]int{}
+	}
+
+	nb.samples++
+	nb.docs[category]++
+	for _, t := range words(summary) {
+		nb.tokens[category][t]++
+		nb.tokensTotal[category]++
+		nb.vocabulary[t] = nil
+	}
+}
+
+// Classify returns the category with the highest posterior probability, which
+// is used as confidence. It uses Laplace smoothing for unknown words and
+// does not guess if none of the words is known.
+func (nb *NaiveBayes) Classify(summary string) (string, float64) {
+	// without any known word, the guess would be based on the priors only
+	ts := words(summary)
+	known := false
+	for _, t := range ts {
+		_, ok := nb.vocabulary[t]
+		known = known || ok
+	}
+	if !known {
+		return "", 0
+	}
+
+	cns := make([]string, 0, len(nb.docs))
+	for cn := range nb.docs {
+		cns = append(cns, cn)
+	}
+	sort.Strings(cns)
+
+	v := float64(len(nb.vocabulary))
+	logps := make([]float64, len(cns))
+	best := 0
+	for i, cn := range cns {
+		logp := math.Log(float64(nb.docs[cn]) / float64(nb.samples))
+		for _, t := range ts {
+			n := float64(nb.tokens[cn][t])
+			logp += math.Log((n + 1) / (float64(nb.tokensTotal[cn]) + v))
+		}
+		logps[i] = logp
+		if logp > logps[best] {
+			best = i
+		}
+	}
+
+	// normalize the probabilities, i.e., divide by their sum
+	sum := 0.0
+	for _, logp := range logps {
+		sum += math.Exp(logp - logps[best])
+	}
+	return cns[best], 1 / sum
+}
+
+// words splits a summary into lower case words.
+func words(s string) []string {
+	return tokenPattern.FindAllString(strings.ToLower(s), -1)
+}
@@ -0,0 +1,45 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat_test
+
+import (
+	"testing"
+
+	. "github.com/abc-inc/cal2cat/cat"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestNaiveBayes_Classify(t *testing.T) {
+	nb := NewNaiveBayes()
+	cn, conf := nb.Classify("ABC")
+	Equal(t, "", cn)
+	Equal(t, 0.0, conf)
+
+	nb.Train("ABC: Review", "Project ABC")
+	nb.Train("ABC: Kickoff", "Project ABC")
+	nb.Train("XYZ Review", "Project XYZ")
+	nb.Train("Community of Practice", "Info Meeting")
+
+	cn, conf = nb.Classify("abc testing")
+	Equal(t, "Project ABC", cn)
+	Greater(t, conf, 0.5)
+	LessOrEqual(t, conf, 1.0)
+
+	cn, _ = nb.Classify("XYZ Testing")
+	Equal(t, "Project XYZ", cn)
+
+	cn, _ = nb.Classify("Lunch")
+	Equal(t, "", cn)
+}
@@ -0,0 +1,229 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/cal2cat/event"
+)
+
+// dateFmt is the layout of dates in conditions.
+const dateFmt = "2006-01-02"
+
+// maxDuration is the longest representable duration.
+const maxDuration = time.Duration(1<<63 - 1)
+
+var (
+	clockPattern    = regexp.MustCompile(`^(\d{1,2}:\d{2})?-(\d{1,2}:\d{2})?$`)
+	durationPattern = regexp.MustCompile(`^(<=|>=|<|>)(.+)$`)
+	datePattern     = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})?\.\.(\d{4}-\d{2}-\d{2})?$`)
+
+	weekdays = map[string]time.Weekday{
+		"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday,
+		"wed": time.Wednesday, "thu": time.Thursday, "fri": time.Friday,
+		"sat": time.Saturday,
+	}
+)
+
+// NewWeekdayFilter returns a new Filter, which checks whether an event starts
+// on any of the given weekdays.
+func NewWeekdayFilter(days ...time.Weekday) event.Filter {
+	return func(e event.Wrapper) bool {
+		wd := e.StartTime().Weekday()
+		for _, d := range days {
+			if wd == d {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// NewTimeOfDayFilter returns a new Filter, which checks whether an event
+// starts at or after from and before to. Both are offsets from midnight.
+// If from is after to, the range wraps around midnight, e.g., from 22:00 until
+// 06:00 the next day.
+func NewTimeOfDayFilter(from, to time.Duration) event.Filter {
+	return func(e event.Wrapper) bool {
+		t := e.StartTime()
+		d := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+			time.Duration(t.Second())*time.Second
+		if from > to {
+			return d >= from || d < to
+		}
+		return d >= from && d < to
+	}
+}
+
+// NewDateRangeFilter returns a new Filter, which checks whether an event
+// starts at or after from and before until. Zero times denote open ends.
+func NewDateRangeFilter(from, until time.Time) event.Filter {
+	return func(e event.Wrapper) bool {
+		t := e.StartTime()
+		return (from.IsZero() || !t.Before(from)) && (until.IsZero() || t.Before(until))
+	}
+}
+
+// ParseCondition parses a condition (without the leading '@'):
+//
+//	mon, mon-fri, sat,sun       weekdays (ranges and lists)
+//	08:00-12:00, 12:00-, -08:00 start time of day (end exclusive)
+//	22:00-06:00                 start time of day (wrapping around midnight)
+//	>4h, >=1h, <15m, <=30m      duration (see time.ParseDuration)
+//	2024-01-01..2024-03-31      start date (both inclusive, either optional)
+func ParseCondition(s string) (event.Filter, error) {
+	switch {
+	case clockPattern.MatchString(s):
+		m := clockPattern.FindStringSubmatch(s)
+		from, to := time.Duration(0), 24*time.Hour
+		var err error
+		if m[1] != "" {
+			if from, err = ParseClock(m[1]); err != nil {
+				return nil, err
+			}
+		}
+		if m[2] != "" {
+			if to, err = ParseClock(m[2]); err != nil {
+				return nil, err
+			}
+		}
+		if from == to {
+			return nil, fmt.Errorf("empty time range %q", s)
+		}
+		return NewTimeOfDayFilter(from, to), nil
+	case durationPattern.MatchString(s):
+		m := durationPattern.FindStringSubmatch(s)
+		d, err := time.ParseDuration(m[2])
+		if err != nil {
+			return nil, err
+		}
+		switch m[1] {
+		case "<":
+			return event.NewDurationFilter(0, d-1), nil
+		case "<=":
+			return event.NewDurationFilter(0, d), nil
+		case ">":
+			return event.NewDurationFilter(d+1, maxDuration), nil
+		default:
+			return event.NewDurationFilter(d, maxDuration), nil
+		}
+	case datePattern.MatchString(s):
+		from, until, err := ParseDateRange(s)
+		if err != nil {
+			return nil, err
+		}
+		return NewDateRangeFilter(from, until), nil
+	}
+
+	days, err := ParseWeekdays(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q", s)
+	}
+	return NewWeekdayFilter(days...), nil
+}
+
+// ParseDateRange parses a range of dates like "2024-01-01..2024-03-31" in the
+// local time zone, where both dates are inclusive and either is optional.
+// It returns the start of the first day and the end of the last day, or zero
+// times for open ends.
+func ParseDateRange(s string) (from, until time.Time, err error) {
+	m := datePattern.FindStringSubmatch(s)
+	if m == nil {
+		return from, until, fmt.Errorf("invalid date range %q", s)
+	}
+	if from, err = parseDate(m[1]); err != nil {
+		return
+	}
+	if until, err = parseDate(m[2]); err != nil || until.IsZero() {
+		return
+	}
+	if until = until.AddDate(0, 0, 1); !from.IsZero() && !from.Before(until) {
+		err = fmt.Errorf("invalid date range %q: start after end", s)
+	}
+	return
+}
+
+// FormatDateRange formats a range of dates returned by ParseDateRange.
+func FormatDateRange(from, until time.Time) string {
+	s := ""
+	if !from.IsZero() {
+		s += from.Format(dateFmt)
+	}
+	s += ".."
+	if !until.IsZero() {
+		s += until.AddDate(0, 0, -1).Format(dateFmt)
+	}
+	return s
+}
+
+// ParseClock parses a time of day from "00:00" to "24:00" as offset from
+// midnight.
+func ParseClock(s string) (time.Duration, error) {
+	var h, m int
+	if _, err := fmt.Sscanf(strings.TrimSpace(s), "%d:%d", &h, &m); err != nil ||
+		h < 0 || m < 0 || h > 24 || m > 59 || h == 24 && m > 0 {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// parseDate parses a date in the local time zone. An empty string results in
+// the zero time.
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(dateFmt, s, time.Local)
+}
+
+// ParseWeekdays parses a comma-separated list of weekdays and weekday ranges
+// like "mon-fri" or "sat,sun".
+func ParseWeekdays(s string) ([]time.Weekday, error) {
+	days := []time.Weekday{}
+	for _, p := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(p, "-")
+		f, ok := parseWeekday(from)
+		if !ok {
+			return nil, fmt.Errorf("invalid weekdays %q", s)
+		}
+		t := f
+		if isRange {
+			if t, ok = parseWeekday(to); !ok {
+				return nil, fmt.Errorf("invalid weekdays %q", s)
+			}
+		}
+		for d := f; ; d = (d + 1) % 7 {
+			days = append(days, d)
+			if d == t {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// parseWeekday parses an English weekday name (or its first three letters).
+func parseWeekday(s string) (time.Weekday, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) < 3 {
+		return 0, false
+	}
+	d, ok := weekdays[s[:3]]
+	return d, ok && strings.HasPrefix(strings.ToLower(d.String()), s)
+}
@@ -0,0 +1,126 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/abc-inc/cal2cat/cat"
+	"github.com/abc-inc/cal2cat/event"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestParseCondition(t *testing.T) {
+	// Friday, 28.05.2021 14:30 - 16:00
+	fri := time.Date(2021, 5, 28, 14, 30, 0, 0, time.Local)
+	e := event.NewSimpleEvent(fri, fri.Add(90*time.Minute), "Review")
+
+	tests := []struct {
+		cond string
+		want bool
+	}{
+		{"fri", true},
+		{"Friday", true},
+		{"mon-thu", false},
+		{"mon-fri", true},
+		{"fri-mon", true},
+		{"sat,sun", false},
+		{"tue,fri", true},
+		{"12:00-", true},
+		{"-12:00", false},
+		{"08:00-14:30", false},
+		{"14:30-15:00", true},
+		{"22:00-06:00", false},
+		{"14:00-08:00", true},
+		{"15:00-14:30", false},
+		{"-24:00", true},
+		{">1h", true},
+		{">90m", false},
+		{">=90m", true},
+		{"<90m", false},
+		{"<=1h30m", true},
+		{"2021-05-28..2021-05-28", true},
+		{"2021-05-29..", false},
+		{"..2021-05-27", false},
+		{"2021-01-01..", true},
+	}
+
+	for _, tt := range tests {
+		f, err := ParseCondition(tt.cond)
+		NoError(t, err, tt.cond)
+		Equal(t, tt.want, f(e), tt.cond)
+	}
+
+	for _, c := range []string{"fr", "freitag", "mon-xyz", "25:00-", "24:30-", "-24:01", "08:00-08:00", ">4x", "2021-13-01.."} {
+		_, err := ParseCondition(c)
+		Error(t, err, c)
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		s    string
+		want time.Duration
+	}{
+		{"00:00", 0},
+		{" 08:30", 8*time.Hour + 30*time.Minute},
+		{"24:00", 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		d, err := ParseClock(tt.s)
+		NoError(t, err, tt.s)
+		Equal(t, tt.want, d, tt.s)
+	}
+
+	for _, s := range []string{"", "8", "-1:00", "08:-30", "08:60", "24:01", "25:00"} {
+		_, err := ParseClock(s)
+		Error(t, err, s)
+	}
+}
+
+func TestNewRule_Conditions(t *testing.T) {
+	fri := time.Date(2021, 5, 28, 14, 30, 0, 0, time.Local)
+	e := event.NewSimpleEvent(fri, fri.Add(5*time.Hour), "ABC Workshop")
+
+	r, err := NewRule("ABC Workshop @fri @12:00-", "Learning", 1)
+	NoError(t, err)
+	True(t, r.MatchEvent(e, e.Summary()))
+	False(t, r.MatchEvent(e, "XYZ"))
+
+	r, err = NewRule("@>4h", "Workshop", 1)
+	NoError(t, err)
+	True(t, r.Match("anything"))
+	True(t, r.MatchEvent(e, e.Summary()))
+	False(t, r.MatchEvent(event.NewSimpleEvent(fri, fri.Add(time.Hour), "ABC"), "ABC"))
+
+	r, err = NewRule("*@fri* @mon", "Other", 1)
+	NoError(t, err)
+	False(t, r.MatchEvent(event.NewSimpleEvent(fri, fri, "x @fri y"), "x @fri y"))
+
+	_, err = NewRule("ABC @someday", "ABC", 1)
+	Error(t, err)
+}
+
+func TestParseDateRange(t *testing.T) {
+	for _, s := range []string{"2021-01-01..2021-03-31", "2021-05-28..2021-05-28", "2021-04-01..", "..2021-03-31"} {
+		from, until, err := ParseDateRange(s)
+		NoError(t, err, s)
+		Equal(t, s, FormatDateRange(from, until))
+	}
+
+	_, _, err := ParseDateRange("2021-03-31..2021-01-01")
+	Error(t, err)
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultFuzzyThreshold is the minimum similarity of fuzzy patterns, unless
+// specified otherwise (see WithFuzzyThreshold).
+const DefaultFuzzyThreshold = 0.8
+
+// NewFuzzyMatcher creates a new Matcher, which accepts strings containing a
+// sequence of words similar to the pattern (see Similarity). The threshold is
+// the minimum similarity between 0 and 1.
+//
+// An error is returned if the pattern has no words or the threshold is out of
+// range.
+func NewFuzzyMatcher(pattern string, threshold float64) (Matcher, error) {
+	if len(words(pattern)) == 0 {
+		return nil, fmt.Errorf("fuzzy pattern %q contains no words", pattern)
+	}
+	if threshold < 0 || threshold > 1 {
+		return nil, fmt.Errorf("invalid similarity threshold %v: must be between 0 and 1", threshold)
+	}
+	return func(s string) bool {
+		return Similarity(pattern, s) >= threshold
+	}, nil
+}
+
+// Similarity compares the words of the pattern with all sequences of
+// (roughly) the same number of consecutive words in s, ignoring case and
+// punctuation. It returns the highest similarity between 0 and 1, which is
+// based on the edit distance, e.g., "Comunity of Practise: Security" is 90%
+// similar to "Community of Practice".
+func Similarity(pattern, s string) float64 {
+	pw, sw := words(pattern), words(s)
+	if len(pw) == 0 {
+		return 0
+	}
+
+	p := []rune(strings.Join(pw, " "))
+	best := 0.0
+	for i := range sw {
+		for n := len(pw) - 1; n <= len(pw)+1; n++ {
+			if n < 1 || i+n > len(sw) {
+				continue
+			}
+			w := []rune(strings.Join(sw[i:i+n], " "))
+			l := len(p)
+			if len(w) > l {
+				l = len(w)
+			}
+			if sim := 1 - float64(levenshtein(p, w))/float64(l); sim > best {
+				best = sim
+			}
+		}
+	}
+	return best
+}
+
+// levenshtein computes the edit distance between a and b.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// minInt returns the smallest of the given numbers.
+func minInt(n int, ns ...int) int {
+	for _, m := range ns {
+		if m < n {
+			n = m
+		}
+	}
+	return n
+}
@@ -0,0 +1,59 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat_test
+
+import (
+	"testing"
+
+	. "github.com/abc-inc/cal2cat/cat"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestSimilarity(t *testing.T) {
+	Equal(t, 1.0, Similarity("Community of Practice", "Community of Practice: Security"))
+	InDelta(t, 0.9, Similarity("Community of Practice", "Comunity of Practise: Security"), 0.01)
+	InDelta(t, 0.9, Similarity("community of practice", "Security - Comunity of Practise"), 0.01)
+	Less(t, Similarity("Community of Practice", "Coffee Break"), 0.5)
+	Equal(t, 0.0, Similarity("", "Coffee Break"))
+	Equal(t, 0.0, Similarity("Coffee", ""))
+}
+
+func TestNewMatcher_Fuzzy(t *testing.T) {
+	m, err := NewMatcher("~Community of Practice")
+	NoError(t, err)
+	True(t, m("Comunity of Practise: Security"))
+	False(t, m("Commute"))
+
+	r, err := NewRule("~Community of Practice", "Info Meeting", 1)
+	NoError(t, err)
+	True(t, r.Fuzzy())
+	x := Mapping{Rules: []Rule{r}}.Explain(newEvent("Comunity of Practise"))
+	Equal(t, r.Pattern, x.Match.Pattern)
+	InDelta(t, 0.9, x.Similarity, 0.01)
+}
+
+func TestNewMatcher_FuzzyThreshold(t *testing.T) {
+	m, err := NewMatcher("~Community of Practice", WithFuzzyThreshold(0.95))
+	NoError(t, err)
+	True(t, m("Community of Practice: Security"))
+	False(t, m("Comunity of Practise: Security"))
+
+	_, err = NewMatcher("~Community of Practice", WithFuzzyThreshold(1.5))
+	Error(t, err)
+	_, err = NewMatcher("~")
+	Error(t, err)
+	_, err = NewRule("~ @fri", "Other", 1)
+	Error(t, err)
+}
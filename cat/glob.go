@@ -0,0 +1,305 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrBadGlob indicates that a wildcard pattern is malformed.
+var ErrBadGlob = errors.New("syntax error in pattern")
+
+// Glob is a compiled wildcard pattern for event summaries.
+//
+// Unlike path.Match, there is no path separator, i.e., '*' matches any
+// sequence of characters including '/'.
+type Glob struct {
+	pattern string
+	alts    [][]token
+}
+
+// tokenKind enumerates the elements a wildcard pattern consists of.
+type tokenKind int
+
+const (
+	literal tokenKind = iota
+	anyChar
+	anySeq
+	class
+)
+
+// token is a single element of a compiled wildcard pattern.
+type token struct {
+	kind    tokenKind
+	r       rune
+	negated bool
+	ranges  []runeRange
+}
+
+// runeRange is an inclusive range of runes in a character class.
+type runeRange struct {
+	lo, hi rune
+}
+
+// CompileGlob parses a wildcard pattern.
+//
+// The pattern syntax is:
+//
+//	'*'         matches any sequence of characters
+//	'?'         matches any single character
+//	'[' [ '!' | '^' ] { range } ']'
+//	            matches a single character in (or not in) the class
+//	'{' a ',' b ... '}'
+//	            matches any of the comma-separated alternatives
+//	'\' c       matches the character c literally
+//
+// where range is either a single character c or c '-' c.
+func CompileGlob(pattern string) (*Glob, error) {
+	alts, err := expand(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Glob{pattern: pattern}
+	for _, a := range alts {
+		ts, err := tokenize(a)
+		if err != nil {
+			return nil, err
+		}
+		g.alts = append(g.alts, ts)
+	}
+	return g, nil
+}
+
+// String returns the source text of the pattern.
+func (g Glob) String() string {
+	return g.pattern
+}
+
+// Match reports whether the whole string s matches the pattern.
+func (g Glob) Match(s string) bool {
+	for _, ts := range g.alts {
+		if matchTokens(ts, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// expand resolves brace alternatives and returns all resulting patterns.
+// Escape sequences are kept as they are, so that tokenize can handle them.
+func expand(p string) ([]string, error) {
+	start := -1
+	depth := 0
+	commas := []int{}
+	for i := 0; i < len(p); i++ {
+		switch p[i] {
+		case '\\':
+			i++
+			if i >= len(p) {
+				return nil, fmt.Errorf("%w: trailing backslash in %q", ErrBadGlob, p)
+			}
+		case '[':
+			end := classEnd(p, i)
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated '[' in %q", ErrBadGlob, p)
+			}
+			i = end
+		case '{':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case ',':
+			if depth == 1 {
+				commas = append(commas, i)
+			}
+		case '}':
+			if depth == 0 {
+				return nil, fmt.Errorf("%w: unexpected '}' in %q", ErrBadGlob, p)
+			}
+			depth--
+			if depth > 0 {
+				continue
+			}
+
+			prefix, suffix := p[:start], p[i+1:]
+			bounds := append(append([]int{start}, commas...), i)
+			var res []string
+			for k := 0; k < len(bounds)-1; k++ {
+				es, err := expand(prefix + p[bounds[k]+1:bounds[k+1]] + suffix)
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, es...)
+			}
+			return res, nil
+		}
+	}
+
+	if depth > 0 {
+		return nil, fmt.Errorf("%w: unterminated '{' in %q", ErrBadGlob, p)
+	}
+	return []string{p}, nil
+}
+
+// classEnd returns the index of the ']' closing the character class, which
+// starts at index i, or -1 if the class is not terminated.
+func classEnd(p string, i int) int {
+	i++
+	if i < len(p) && (p[i] == '!' || p[i] == '^') {
+		i++
+	}
+	// a ']' immediately after the opening bracket is a literal
+	if i < len(p) && p[i] == ']' {
+		i++
+	}
+	for ; i < len(p); i++ {
+		switch p[i] {
+		case '\\':
+			i++
+		case ']':
+			return i
+		}
+	}
+	return -1
+}
+
+// tokenize compiles a pattern without braces into a sequence of tokens.
+func tokenize(p string) ([]token, error) {
+	var ts []token
+	for i := 0; i < len(p); {
+		r, n := utf8.DecodeRuneInString(p[i:])
+		switch r {
+		case '*':
+			// consecutive stars are equivalent to a single one
+			if len(ts) == 0 || ts[len(ts)-1].kind != anySeq {
+				ts = append(ts, token{kind: anySeq})
+			}
+			i += n
+		case '?':
+			ts = append(ts, token{kind: anyChar})
+			i += n
+		case '\\':
+			r, m := utf8.DecodeRuneInString(p[i+n:])
+			ts = append(ts, token{kind: literal, r: r})
+			i += n + m
+		case '[':
+			end := classEnd(p, i)
+			t, err := parseClass(p[i+1 : end])
+			if err != nil {
+				return nil, fmt.Errorf("%w in %q", err, p)
+			}
+			ts = append(ts, t)
+			i = end + 1
+		default:
+			ts = append(ts, token{kind: literal, r: r})
+			i += n
+		}
+	}
+	return ts, nil
+}
+
+// parseClass parses the content of a character class (without brackets).
+func parseClass(c string) (token, error) {
+	t := token{kind: class}
+	if strings.HasPrefix(c, "!") || strings.HasPrefix(c, "^") {
+		t.negated = true
+		c = c[1:]
+	}
+	if c == "" {
+		return t, fmt.Errorf("%w: empty character class", ErrBadGlob)
+	}
+
+	rs := []rune{}
+	for i := 0; i < len(c); {
+		r, n := utf8.DecodeRuneInString(c[i:])
+		if r == '\\' {
+			i += n
+			r, n = utf8.DecodeRuneInString(c[i:])
+		}
+		rs = append(rs, r)
+		i += n
+		// mark the position of an unescaped '-' with a sentinel
+		if i < len(c)-1 && c[i] == '-' {
+			rs = append(rs, utf8.MaxRune+1)
+			i++
+		}
+	}
+
+	for i := 0; i < len(rs); i++ {
+		rr := runeRange{rs[i], rs[i]}
+		if i+2 < len(rs) && rs[i+1] == utf8.MaxRune+1 {
+			rr.hi = rs[i+2]
+			i += 2
+		}
+		if rr.lo > rr.hi {
+			return t, fmt.Errorf("%w: invalid range %c-%c", ErrBadGlob, rr.lo, rr.hi)
+		}
+		t.ranges = append(t.ranges, rr)
+	}
+	return t, nil
+}
+
+// matches checks whether the token accepts a single rune.
+func (t token) matches(r rune) bool {
+	switch t.kind {
+	case literal:
+		return t.r == r
+	case anyChar:
+		return true
+	case class:
+		for _, rr := range t.ranges {
+			if rr.lo <= r && r <= rr.hi {
+				return !t.negated
+			}
+		}
+		return t.negated
+	}
+	return false
+}
+
+// matchTokens matches s against the tokens. It remembers the position of the
+// last star and backtracks to it, which avoids exponential runtime.
+func matchTokens(ts []token, s string) bool {
+	ti, si := 0, 0
+	starTi, starSi := -1, 0
+	for si < len(s) {
+		r, n := utf8.DecodeRuneInString(s[si:])
+		switch {
+		case ti < len(ts) && ts[ti].kind == anySeq:
+			starTi, starSi = ti, si
+			ti++
+		case ti < len(ts) && ts[ti].matches(r):
+			ti++
+			si += n
+		case starTi >= 0:
+			// let the last star consume one more character and retry
+			_, m := utf8.DecodeRuneInString(s[starSi:])
+			starSi += m
+			ti, si = starTi+1, starSi
+		default:
+			return false
+		}
+	}
+
+	for ti < len(ts) && ts[ti].kind == anySeq {
+		ti++
+	}
+	return ti == len(ts)
+}
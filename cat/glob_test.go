@@ -0,0 +1,88 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/abc-inc/cal2cat/cat"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestGlob_Match(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		want    bool
+	}{
+		{"*ABC*", "Review A/B ABC", true},
+		{"*ABC*", "ABC", true},
+		{"*ABC*", "AB C", false},
+		{"ABC?", "ABCD", true},
+		{"ABC?", "ABC", false},
+		{"?ber*", "Über alles", true},
+		{"[AB]*", "Bravo", true},
+		{"[!AB]*", "Bravo", false},
+		{"[^AB]*", "Charlie", true},
+		{"[a-c]x", "bx", true},
+		{"[a-c]x", "dx", false},
+		{"[]]", "]", true},
+		{"[a-]", "-", true},
+		{"*{ABC,ABD}*", "Kickoff ABD", true},
+		{"*{ABC,ABD}*", "Kickoff ABE", false},
+		{"{A{1,2},B}", "A2", true},
+		{"{A{1,2},B}", "B", true},
+		{"{A{1,2},B}", "A3", false},
+		{"{,x}y", "y", true},
+		{`\*ABC\*`, "*ABC*", true},
+		{`\*ABC\*`, "xABCx", false},
+		{`a\{b,c\}`, "a{b,c}", true},
+		{"*a*a*a*a*b", "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", false},
+	}
+
+	for _, tt := range tests {
+		g, err := CompileGlob(tt.pattern)
+		NoError(t, err, tt.pattern)
+		Equal(t, tt.want, g.Match(tt.s), "%s ~ %s", tt.pattern, tt.s)
+	}
+}
+
+func TestCompileGlob_Invalid(t *testing.T) {
+	for _, p := range []string{"[abc", "{a,b", "a}", `abc\`, "[z-a]", "[]"} {
+		_, err := CompileGlob(p)
+		True(t, errors.Is(err, ErrBadGlob), p)
+	}
+}
+
+func TestNewMatcher(t *testing.T) {
+	m, err := NewMatcher("ABC")
+	NoError(t, err)
+	True(t, m("ABC: Review"))
+	False(t, m("Review ABC"))
+
+	m, err = NewMatcher("*ABC*")
+	NoError(t, err)
+	True(t, m("Review A/B ABC"))
+
+	m, err = NewMatcher("^.*Problem|Incident")
+	NoError(t, err)
+	True(t, m("Incident 42"))
+
+	_, err = NewMatcher("^(ABC")
+	Error(t, err)
+	_, err = NewMatcher("*[ABC*")
+	Error(t, err)
+}
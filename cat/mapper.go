@@ -15,7 +15,6 @@
 package cat
 
 import (
-	"path"
 	"regexp"
 	"strings"
 )
@@ -26,40 +25,82 @@ type Matcher func(string) bool
 // Mapper maps strings to other strings.
 type Mapper func(string) string
 
+// Option customizes how NewMatcher and NewRule compile patterns.
+type Option func(*options)
+
+// options are the settings for compiling patterns.
+type options struct {
+	fuzzyThreshold float64
+}
+
+// WithFuzzyThreshold sets the minimum similarity of fuzzy patterns.
+func WithFuzzyThreshold(threshold float64) Option {
+	return func(o *options) {
+		o.fuzzyThreshold = threshold
+	}
+}
+
+// newOptions applies the Options to the defaults.
+func newOptions(opts []Option) options {
+	o := options{fuzzyThreshold: DefaultFuzzyThreshold}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
 // NewMatcher creates a new Matcher for the pattern.
 //
 // The matching operation depends on the pattern:
 //
 // - starts with '^': use regular expression
 //
-// - contains any of "*?[": use globing
+// - starts with '~': use fuzzy matching (see NewFuzzyMatcher) with the
+// DefaultFuzzyThreshold or the one given by WithFuzzyThreshold
+//
+// - contains any of "*?[{": use globing (see CompileGlob)
 //
 // - otherwise: prefix search
-func NewMatcher(pattern string) Matcher {
+//
+// An error is returned if the pattern is not a valid regular expression,
+// wildcard or fuzzy pattern.
+func NewMatcher(pattern string, opts ...Option) (Matcher, error) {
+	if strings.HasPrefix(pattern, "~") {
+		return NewFuzzyMatcher(pattern[1:], newOptions(opts).fuzzyThreshold)
+	}
+
 	if strings.HasPrefix(pattern, "^") {
-		return regexp.MustCompile(pattern).MatchString
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString, nil
 	}
 
-	if strings.ContainsAny(pattern, "*?[") {
-		return func(s string) bool {
-			ok, err := path.Match(pattern, s)
-			return err == nil && ok
+	if strings.ContainsAny(pattern, "*?[{") {
+		g, err := CompileGlob(pattern)
+		if err != nil {
+			return nil, err
 		}
+		return g.Match, nil
 	}
 
 	return func(s string) bool {
 		return strings.HasPrefix(s, pattern)
-	}
+	}, nil
 }
 
 // NewMapper creates a new Mapper, which checks if the input matches pattern
 // and returns either str or an empty string.
-func NewMapper(pattern, str string) Mapper {
-	m := NewMatcher(pattern)
+func NewMapper(pattern, str string) (Mapper, error) {
+	m, err := NewMatcher(pattern)
+	if err != nil {
+		return nil, err
+	}
 	return func(s string) string {
 		if m(s) {
 			return str
 		}
 		return ""
-	}
+	}, nil
 }
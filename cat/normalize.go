@@ -0,0 +1,119 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Normalizer transforms a string before it is matched.
+type Normalizer func(string) string
+
+var (
+	spacePattern = regexp.MustCompile(`\s+`)
+	tagPattern   = regexp.MustCompile(`\[[^\[\]]*\]`)
+)
+
+// Chain creates a Normalizer, which applies all Normalizers in the given order.
+func Chain(ns ...Normalizer) Normalizer {
+	return func(s string) string {
+		for _, n := range ns {
+			s = n(s)
+		}
+		return s
+	}
+}
+
+// NewPrefixStripper creates a new Normalizer, which removes any of the
+// prefixes (e.g., "Canceled:" or "FW:") from the beginning of a string.
+// Prefixes are compared case-insensitively and removed repeatedly, so that
+// "FW: WG: Review" becomes "Review".
+func NewPrefixStripper(prefixes ...string) Normalizer {
+	ps := make([]string, 0, len(prefixes))
+	for _, p := range prefixes {
+		if p = strings.TrimSpace(p); p != "" {
+			ps = append(ps, p)
+		}
+	}
+
+	return func(s string) string {
+		for stripped := true; stripped; {
+			stripped = false
+			s = strings.TrimLeftFunc(s, unicode.IsSpace)
+			for _, p := range ps {
+				if len(s) >= len(p) && strings.EqualFold(s[:len(p)], p) {
+					s, stripped = s[len(p):], true
+				}
+			}
+		}
+		return s
+	}
+}
+
+// NewRewriter creates a new Normalizer, which replaces all matches of the
+// regular expression with repl. Inside repl, $ signs are interpreted as in
+// regexp.Regexp.Expand.
+func NewRewriter(pattern, repl string) (Normalizer, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return func(s string) string {
+		return re.ReplaceAllString(s, repl)
+	}, nil
+}
+
+// CollapseWhitespace replaces consecutive whitespace by a single space and
+// trims leading and trailing whitespace.
+func CollapseWhitespace(s string) string {
+	return strings.TrimSpace(spacePattern.ReplaceAllString(s, " "))
+}
+
+// RemoveEmoji removes pictographic symbols and their modifiers.
+func RemoveEmoji(s string) string {
+	return strings.Map(func(r rune) rune {
+		if isEmoji(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// RemoveTags removes bracketed tags like "[EXT]" or "[Team A]".
+func RemoveTags(s string) string {
+	return tagPattern.ReplaceAllString(s, "")
+}
+
+// isEmoji checks whether the rune is an emoji, a pictograph or a character
+// used to compose emoji sequences.
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // pictographs, emoticons, flags, etc.
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // miscellaneous symbols and dingbats
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // arrows, stars, etc.
+		return true
+	case r >= 0xFE00 && r <= 0xFE0F: // variation selectors
+		return true
+	case r == 0x200D || r == 0x20E3: // zero width joiner, keycap
+		return true
+	case r >= 0xE0020 && r <= 0xE007F: // tags used in subdivision flags
+		return true
+	}
+	return false
+}
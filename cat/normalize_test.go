@@ -0,0 +1,44 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat_test
+
+import (
+	"testing"
+
+	. "github.com/abc-inc/cal2cat/cat"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestNewPrefixStripper(t *testing.T) {
+	n := NewPrefixStripper("Canceled:", "FW:", " WG: ")
+	Equal(t, "Review", n("Canceled: Review"))
+	Equal(t, "Review", n("fw: WG:Review"))
+	Equal(t, "Review FW: ABC", n("Review FW: ABC"))
+}
+
+func TestNewRewriter(t *testing.T) {
+	n, err := NewRewriter(`(?i)^jour fixe`, "JF")
+	NoError(t, err)
+	Equal(t, "JF ABC", n("Jour Fixe ABC"))
+
+	_, err = NewRewriter(`(`, "")
+	Error(t, err)
+}
+
+func TestChain(t *testing.T) {
+	n := Chain(RemoveTags, RemoveEmoji, NewPrefixStripper("Updated:"), CollapseWhitespace)
+	Equal(t, "ABC: Review", n("[EXT] Updated: 🚀 ABC:\tReview 👍🏽 "))
+	Equal(t, "", Chain()(""))
+}
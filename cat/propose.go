@@ -0,0 +1,89 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// separators delimit the leading part of a summary, e.g., "ABC: Review".
+	separators = []string{":", " - ", " | ", "(", "/"}
+
+	// conditionStart matches an '@' at the beginning of a word, which would
+	// otherwise start a condition (see NewRule).
+	conditionStart = regexp.MustCompile(`(^|\s)@`)
+)
+
+// ProposePatterns derives patterns from a (normalized) summary, which match
+// the summary. The patterns are ordered from the most general to the most
+// specific one: prefix, wildcard and regular expression.
+func ProposePatterns(summary string) []string {
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		return nil
+	}
+
+	head := Head(summary)
+	return []string{
+		prefixPattern(head),
+		"*" + EscapeGlob(head) + "*",
+		"^" + escapeConditions(regexp.QuoteMeta(summary)) + "$",
+	}
+}
+
+// prefixPattern returns a pattern matching all strings starting with s.
+// If s contains special characters, a wildcard pattern is returned.
+func prefixPattern(s string) string {
+	if strings.ContainsAny(s, "*?[{\\") || strings.HasPrefix(s, "^") || strings.HasPrefix(s, "~") ||
+		conditionStart.MatchString(s) {
+		return EscapeGlob(s) + "*"
+	}
+	return s
+}
+
+// Head returns the leading part of a summary up to the first separator like
+// ':' or " - ". If there is no separator, the whole summary is returned.
+func Head(summary string) string {
+	i := len(summary)
+	for _, sep := range separators {
+		if j := strings.Index(summary, sep); j > 0 && j < i {
+			i = j
+		}
+	}
+	return strings.TrimSpace(summary[:i])
+}
+
+// EscapeGlob escapes all characters with special meaning in wildcard patterns
+// as well as '^' and '~', which denote a regular expression and a fuzzy
+// pattern at the beginning of a pattern, and '@' at the beginning of a word,
+// which denotes a condition.
+func EscapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]{}^~\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return escapeConditions(b.String())
+}
+
+// escapeConditions escapes each '@' at the beginning of a word with a
+// backslash, which is valid in wildcard patterns and regular expressions.
+func escapeConditions(s string) string {
+	return conditionStart.ReplaceAllString(s, `${1}\@`)
+}
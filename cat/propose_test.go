@@ -0,0 +1,58 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat_test
+
+import (
+	"testing"
+
+	. "github.com/abc-inc/cal2cat/cat"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestProposePatterns(t *testing.T) {
+	Equal(t, []string{
+		"Community of Practice",
+		"*Community of Practice*",
+		`^Community of Practice: Security$`,
+	}, ProposePatterns("Community of Practice: Security"))
+
+	Equal(t, []string{
+		`\[ABC\] Review*`,
+		`*\[ABC\] Review*`,
+		`^\[ABC\] Review$`,
+	}, ProposePatterns("[ABC] Review"))
+
+	Equal(t, []string{
+		`\~Draft*`,
+		`*\~Draft*`,
+		`^~Draft$`,
+	}, ProposePatterns("~Draft"))
+
+	Equal(t, []string{
+		`Lunch \@home*`,
+		`*Lunch \@home*`,
+		`^Lunch \@home$`,
+	}, ProposePatterns("Lunch @home"))
+
+	Nil(t, ProposePatterns(" "))
+
+	for _, s := range []string{"ABC: Review", "^Weekly? {x}", "Sync - A/B (optional)", "~Draft", "@home Lunch @home"} {
+		for _, p := range ProposePatterns(s) {
+			r, err := NewRule(p, "X", 1)
+			NoError(t, err)
+			True(t, r.Match(s), "%s ~ %s", p, s)
+		}
+	}
+}
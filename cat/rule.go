@@ -0,0 +1,170 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/abc-inc/cal2cat/event"
+)
+
+// Rule assigns a category to events matching a pattern.
+type Rule struct {
+	Pattern  string
+	Category string
+	// Line is the line number in the configuration file (0 if unknown).
+	Line int
+	// Calendar restricts the rule to events of the named calendar (optional).
+	Calendar string
+	// ValidFrom and ValidUntil restrict the rule to events starting at or
+	// after ValidFrom and before ValidUntil. Zero times denote open ends.
+	ValidFrom  time.Time
+	ValidUntil time.Time
+	summary    string
+	match      Matcher
+	conds      []event.Filter
+	allocs     []Allocation
+}
+
+// NewRule creates a new Rule for the pattern.
+//
+// The pattern consists of a summary pattern (see NewMatcher) followed by any
+// number of whitespace-separated conditions starting with '@' (see
+// ParseCondition), e.g., "*ABC* @fri @12:00-". All of them must be satisfied.
+// If there is no summary pattern, all summaries match.
+//
+// The category may distribute the events over several categories (see
+// ParseAllocations).
+func NewRule(pattern, category string, line int, opts ...Option) (Rule, error) {
+	r := Rule{Pattern: pattern, Category: category, Line: line}
+	rest := strings.TrimSpace(pattern)
+	for {
+		i := strings.LastIndexFunc(rest, unicode.IsSpace)
+		tok := rest[i+1:]
+		if !strings.HasPrefix(tok, "@") {
+			break
+		}
+		c, err := ParseCondition(tok[1:])
+		if err != nil {
+			return Rule{}, err
+		}
+		r.conds = append([]event.Filter{c}, r.conds...)
+		rest = strings.TrimSpace(rest[:i+1])
+	}
+	r.summary = rest
+
+	var err error
+	if r.summary != "" {
+		if r.match, err = NewMatcher(r.summary, opts...); err != nil {
+			return Rule{}, err
+		}
+	}
+	if r.allocs, err = ParseAllocations(category); err != nil {
+		return Rule{}, err
+	}
+	return r, nil
+}
+
+// Allocations returns the categories and the fractions of the events
+// assigned to them.
+func (r Rule) Allocations() []Allocation {
+	return r.allocs
+}
+
+// Match checks whether the (normalized) summary matches the summary pattern.
+// Conditions are not checked.
+func (r Rule) Match(s string) bool {
+	return r.match == nil || r.match(s)
+}
+
+// Applies checks whether the rule is neither restricted to another calendar
+// nor out of force when the event starts.
+func (r Rule) Applies(e event.Wrapper) bool {
+	return (r.Calendar == "" || r.Calendar == e.Calendar()) && r.InForce(e.StartTime())
+}
+
+// InForce checks whether the rule is valid at the given time.
+func (r Rule) InForce(t time.Time) bool {
+	return (r.ValidFrom.IsZero() || !t.Before(r.ValidFrom)) &&
+		(r.ValidUntil.IsZero() || t.Before(r.ValidUntil))
+}
+
+// MatchEvent checks whether the rule applies to the event, the event satisfies
+// all conditions and its (normalized) summary matches the summary pattern.
+func (r Rule) MatchEvent(e event.Wrapper, normalized string) bool {
+	if !r.Applies(e) {
+		return false
+	}
+	for _, c := range r.conds {
+		if !c(e) {
+			return false
+		}
+	}
+	return r.Match(normalized)
+}
+
+// Fuzzy checks whether the summary pattern is matched fuzzily.
+func (r Rule) Fuzzy() bool {
+	return strings.HasPrefix(r.summary, "~")
+}
+
+// String returns the rule in configuration syntax.
+func (r Rule) String() string {
+	return fmt.Sprintf("%s=%s", r.Pattern, r.Category)
+}
+
+// Explanation describes how a summary was categorized.
+type Explanation struct {
+	Summary    string
+	Normalized string
+	// Tried contains all rules, which were checked before the matching one,
+	// except for rules of other calendars.
+	Tried []Rule
+	// Match is the first matching rule or nil, if no rule matched.
+	Match *Rule
+	// Similarity is the similarity of the summary, if Match is fuzzy.
+	Similarity float64
+	// Guess is the category guessed by the Classifier, if no rule matched.
+	Guess      string
+	Confidence float64
+}
+
+// Explain checks the event against all rules and records the outcome.
+// If no rule matches, the Classifier is asked for a guess.
+func (m Mapping) Explain(e event.Wrapper) Explanation {
+	x := Explanation{Summary: e.Summary(), Normalized: m.normalize(e.Summary())}
+	for i, r := range m.Rules {
+		if r.MatchEvent(e, x.Normalized) {
+			x.Match = &m.Rules[i]
+			if r.Fuzzy() {
+				x.Similarity = Similarity(r.summary[1:], x.Normalized)
+			}
+			break
+		}
+		if r.Applies(e) {
+			x.Tried = append(x.Tried, r)
+		}
+	}
+
+	if x.Match == nil && m.Classifier != nil {
+		if cn, conf := m.Classifier.Classify(x.Normalized); conf >= m.MinConfidence {
+			x.Guess, x.Confidence = cn, conf
+		}
+	}
+	return x
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/cal2cat/event"
+)
+
+// Allocation is the fraction of an event's duration assigned to a category.
+type Allocation struct {
+	Category string
+	Fraction float64
+}
+
+// Share is the part of an event, which is allocated to a category.
+// Its duration is the fraction of the original duration.
+type Share struct {
+	event.Wrapper
+	Fraction float64
+}
+
+// Duration returns the allocated part of the event duration.
+func (s Share) Duration() time.Duration {
+	return time.Duration(float64(s.Wrapper.Duration()) * s.Fraction)
+}
+
+// Unwrap returns the whole event.
+func (s Share) Unwrap() event.Wrapper {
+	return s.Wrapper
+}
+
+var weightPattern = regexp.MustCompile(`^(.*\S)\s*:\s*(\d+(?:\.\d+)?)$`)
+
+// ParseAllocations parses a comma-separated list of at least two weighted
+// categories like "Project ABC:50,Project XYZ:50". The weights are converted to
+// fractions of their sum. If there is only one part or any part lacks a weight,
+// the whole string is a single category (e.g., "Room:101" or "Info: General"),
+// which gets the whole duration.
+//
+// An error is returned if a category occurs more than once or all weights are
+// zero.
+func ParseAllocations(s string) ([]Allocation, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) < 2 {
+		return []Allocation{{s, 1}}, nil
+	}
+
+	as := make([]Allocation, len(parts))
+	seen := map[string]interface{}{}
+	sum := 0.0
+	for i, p := range parts {
+		m := weightPattern.FindStringSubmatch(strings.TrimSpace(p))
+		if m == nil {
+			return []Allocation{{s, 1}}, nil
+		}
+		if _, ok := seen[m[1]]; ok {
+			return nil, fmt.Errorf("duplicate category %q in %q", m[1], s)
+		}
+		seen[m[1]] = nil
+		w, _ := strconv.ParseFloat(m[2], 64)
+		as[i] = Allocation{m[1], w}
+		sum += w
+	}
+
+	if sum == 0 {
+		return nil, fmt.Errorf("sum of weights is zero in %q", s)
+	}
+	for i := range as {
+		as[i].Fraction /= sum
+	}
+	return as, nil
+}
+
+// allocate assigns the event to the categories. If an event is split, each
+// category gets a Share of the event.
+func allocate(e event.Wrapper, as []Allocation) []assignment {
+	if len(as) == 1 {
+		return []assignment{{as[0].Category, e}}
+	}
+	res := make([]assignment, len(as))
+	for i, a := range as {
+		res[i] = assignment{a.Category, &Share{e, a.Fraction}}
+	}
+	return res
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/abc-inc/cal2cat/cat"
+	"github.com/abc-inc/cal2cat/event"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestParseAllocations(t *testing.T) {
+	as, err := ParseAllocations("Project ABC:50,Project XYZ:50")
+	NoError(t, err)
+	Equal(t, []Allocation{{"Project ABC", 0.5}, {"Project XYZ", 0.5}}, as)
+
+	as, err = ParseAllocations("A: 3, B:1")
+	NoError(t, err)
+	Equal(t, []Allocation{{"A", 0.75}, {"B", 0.25}}, as)
+
+	as, err = ParseAllocations("Info: General, Misc")
+	NoError(t, err)
+	Equal(t, []Allocation{{"Info: General, Misc", 1}}, as)
+
+	as, err = ParseAllocations("Room:101")
+	NoError(t, err)
+	Equal(t, []Allocation{{"Room:101", 1}}, as)
+
+	_, err = ParseAllocations("A:0,B:0")
+	Error(t, err)
+	_, err = ParseAllocations("A:1,B:1,A:2")
+	Error(t, err)
+}
+
+func TestMapping_Map_Split(t *testing.T) {
+	today := time.Now().Truncate(24 * time.Hour)
+	es := event.Events{
+		event.NewSimpleEvent(today, today.Add(2*time.Hour), "Review ABC/XYZ"),
+		event.NewSimpleEvent(today, today.Add(time.Hour), "XYZ"),
+	}
+	r1, err := NewRule("*ABC/XYZ*", "Project ABC:25,Project XYZ:75", 1)
+	NoError(t, err)
+	r2, err := NewRule("XYZ", "Project XYZ", 2)
+	NoError(t, err)
+	Equal(t, []string{"Project ABC", "Project XYZ"}, Mapping{Rules: []Rule{r1, r2}}.Categories())
+
+	cs := Mapping{Rules: []Rule{r1, r2}}.Map(es)
+	Equal(t, 2, len(cs))
+	Equal(t, "Project ABC", cs[0].Name)
+	Equal(t, 30*time.Minute, cs[0].Events.Duration())
+	Equal(t, "Project XYZ", cs[1].Name)
+	Equal(t, 2, len(cs[1].Events))
+	Equal(t, 150*time.Minute, cs[1].Events.Duration())
+
+	sh, ok := cs[1].Events[0].(*Share)
+	True(t, ok)
+	Equal(t, 0.75, sh.Fraction)
+	Equal(t, "Review ABC/XYZ", sh.Summary())
+	Equal(t, 2*time.Hour, sh.Wrapper.Duration())
+}
+
+func TestShare_SplitDays(t *testing.T) {
+	start := time.Date(2021, 5, 24, 22, 0, 0, 0, time.Local)
+	e := event.NewSimpleEvent(start, start.Add(4*time.Hour), "Review ABC/XYZ")
+
+	es := event.Events{&Share{e, 0.25}}.SplitDays(time.Local)
+	Equal(t, 2, len(es))
+	Equal(t, 30*time.Minute, es[0].Duration())
+	Equal(t, 30*time.Minute, es[1].Duration())
+	Equal(t, "Review ABC/XYZ", es[1].Summary())
+}
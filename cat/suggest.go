@@ -0,0 +1,209 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/abc-inc/cal2cat/event"
+)
+
+// Suggestion is a proposed rule, which covers a cluster of similar summaries.
+type Suggestion struct {
+	Rule
+	Events event.Events
+}
+
+var (
+	ticketPattern = regexp.MustCompile(`\b([A-Z][A-Z0-9]+)-\d+\b`)
+	tokenPattern  = regexp.MustCompile(`[\pL\pN][\pL\pN&+.-]*[\pL\pN]`)
+
+	// stopWords are too common to characterize a cluster of summaries.
+	stopWords = map[string]interface{}{
+		"and": nil, "for": nil, "the": nil, "with": nil, "meeting": nil,
+		"call": nil, "sync": nil, "weekly": nil, "daily": nil, "team": nil,
+		"und": nil, "mit": nil, "der": nil, "die": nil, "das": nil, "für": nil,
+	}
+)
+
+// candidate is a potential rule with the events it covers.
+type candidate struct {
+	pattern  string
+	category string
+	// rank prefers ticket keys over prefixes over tokens on equal durations
+	rank   int
+	events event.Events
+	// left and dur are the number and duration of events not covered yet
+	left int
+	dur  time.Duration
+}
+
+// Suggest clusters the events by common prefixes, ticket keys (e.g.,
+// "ABC-123") and shared tokens of their (normalized) summaries. It returns a
+// small set of rules, which covers all events, ordered by total duration.
+//
+// The proposed category of each rule is derived from the pattern and is meant
+// to be edited. An error is returned if a rule cannot be created.
+func Suggest(es event.Events, n Normalizer) ([]Suggestion, error) {
+	m := Mapping{Normalizer: n}
+	summaries := map[event.Wrapper]string{}
+	for _, e := range es {
+		summaries[e] = m.normalize(e.Summary())
+	}
+
+	cands := candidates(es, summaries)
+	byEvent := map[event.Wrapper][]*candidate{}
+	for i := range cands {
+		c := &cands[i]
+		c.left, c.dur = len(c.events), c.events.Duration()
+		for _, e := range c.events {
+			byEvent[e] = append(byEvent[e], c)
+		}
+	}
+
+	covered := map[event.Wrapper]interface{}{}
+	ss := []Suggestion{}
+	for {
+		// greedily pick the candidate covering the longest uncovered time
+		var best *candidate
+		for i := range cands {
+			if c := &cands[i]; c.left > 0 && (best == nil || better(c, best)) {
+				best = c
+			}
+		}
+		if best == nil {
+			break
+		}
+
+		bestEs := uncovered(best.events, covered)
+		for _, e := range bestEs {
+			covered[e] = nil
+			for _, c := range byEvent[e] {
+				c.left, c.dur = c.left-1, c.dur-e.Duration()
+			}
+		}
+		r, err := NewRule(best.pattern, best.category, 0)
+		if err != nil {
+			return nil, err
+		}
+		ss = append(ss, Suggestion{r, bestEs})
+	}
+
+	sort.SliceStable(ss, func(i, j int) bool {
+		return ss[i].Events.Duration() > ss[j].Events.Duration()
+	})
+	return ss, nil
+}
+
+// candidates creates all candidate rules for the events.
+func candidates(es event.Events, summaries map[event.Wrapper]string) []candidate {
+	byPattern := map[string]*candidate{}
+	order := []string{}
+	add := func(pattern, category string, rank int, e event.Wrapper) {
+		c, ok := byPattern[pattern]
+		if !ok {
+			c = &candidate{pattern: pattern, category: category, rank: rank}
+			byPattern[pattern] = c
+			order = append(order, pattern)
+		}
+		if len(c.events) == 0 || c.events[len(c.events)-1] != e {
+			c.events = append(c.events, e)
+		}
+	}
+
+	for _, e := range es {
+		s := summaries[e]
+		if s == "" {
+			continue
+		}
+		for _, m := range ticketPattern.FindAllStringSubmatch(s, -1) {
+			add("*"+m[1]+"-*", m[1], 0, e)
+		}
+
+		h := Head(s)
+		add(prefixPattern(h), h, 1, e)
+
+		for _, t := range tokenPattern.FindAllString(s, -1) {
+			if _, stop := stopWords[strings.ToLower(t)]; stop || !significant(t) {
+				continue
+			}
+			add("*"+EscapeGlob(t)+"*", t, 2, e)
+		}
+
+		// as a last resort, the event is covered by its own summary
+		add(prefixPattern(s), h, 3, e)
+	}
+
+	cs := make([]candidate, 0, len(order))
+	for _, p := range order {
+		c := byPattern[p]
+		// tokens are only useful if they are shared by different summaries
+		if c.rank == 2 && !distinct(c.events, summaries) {
+			continue
+		}
+		cs = append(cs, *c)
+	}
+	return cs
+}
+
+// better checks whether the uncovered events of candidate c cover more time
+// than those of candidate d. On equal durations, more events and lower ranks
+// win.
+func better(c, d *candidate) bool {
+	switch {
+	case c.dur != d.dur:
+		return c.dur > d.dur
+	case c.left != d.left:
+		return c.left > d.left
+	default:
+		return c.rank < d.rank
+	}
+}
+
+// significant checks whether the token is long enough and contains letters.
+func significant(t string) bool {
+	letters := 0
+	for _, r := range t {
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	return letters >= 3
+}
+
+// distinct checks whether the events have at least two different summaries.
+func distinct(es event.Events, summaries map[event.Wrapper]string) bool {
+	for _, e := range es[1:] {
+		if summaries[e] != summaries[es[0]] {
+			return true
+		}
+	}
+	return false
+}
+
+// uncovered returns all events not contained in the given set.
+func uncovered(es event.Events, covered map[event.Wrapper]interface{}) event.Events {
+	res := event.Events{}
+	for _, e := range es {
+		if _, ok := covered[e]; !ok {
+			res = append(res, e)
+		}
+	}
+	return res
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/abc-inc/cal2cat/cat"
+	"github.com/abc-inc/cal2cat/event"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestSuggest(t *testing.T) {
+	today := time.Now().Truncate(24 * time.Hour)
+	ev := func(h int, summary string) event.Wrapper {
+		return event.NewSimpleEvent(today, today.Add(time.Duration(h)*time.Hour), summary)
+	}
+	es := event.Events{
+		ev(1, "ABC: Review"),
+		ev(2, "ABC: Kickoff"),
+		ev(1, "Fix PRJ-12 login"),
+		ev(1, "Discuss PRJ-13"),
+		ev(3, "Coffee"),
+		ev(1, "Retro Frontend"),
+		ev(1, "Frontend Planning"),
+		ev(1, "FW: Coffee"),
+		ev(1, "Lunch @home"),
+	}
+
+	ss, err := Suggest(es, NewPrefixStripper("FW:"))
+	NoError(t, err)
+	Equal(t, 5, len(ss))
+	Equal(t, "Coffee=Coffee", ss[0].String())
+	Equal(t, 2, len(ss[0].Events))
+	Equal(t, "ABC=ABC", ss[1].String())
+	Equal(t, "*PRJ-*=PRJ", ss[2].String())
+	Equal(t, "*Frontend*=Frontend", ss[3].String())
+	Equal(t, `Lunch \@home*=Lunch @home`, ss[4].String())
+
+	covered := 0
+	for _, s := range ss {
+		covered += len(s.Events)
+	}
+	Equal(t, len(es), covered)
+}
@@ -0,0 +1,146 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/cal2cat/event"
+)
+
+// PathSeparator separates the levels of hierarchical category names like
+// "Project ABC/Testing".
+const PathSeparator = "/"
+
+// Node is a category in a hierarchy. Its Name is the full path and its Events
+// are the events categorized exactly by that path, i.e., without the events
+// of its children.
+type Node struct {
+	Category
+	Children []*Node
+}
+
+// Label returns the last level of the category path.
+func (n Node) Label() string {
+	return n.Name[strings.LastIndex(n.Name, PathSeparator)+1:]
+}
+
+// AllEvents returns the events of the node and all its descendants ordered by
+// start time.
+func (n Node) AllEvents() event.Events {
+	es := append(event.Events{}, n.Events...)
+	for _, c := range n.Children {
+		es = append(es, c.AllEvents()...)
+	}
+	sort.SliceStable(es, func(i, j int) bool {
+		return es[i].StartTime().Before(es[j].StartTime())
+	})
+	return es
+}
+
+// Amount returns the billable amount of the node's own events plus the
+// amounts of all its descendants, each according to its own Metadata.
+func (n Node) Amount() float64 {
+	a := n.Metadata.Amount(n.Events.Duration())
+	for _, c := range n.Children {
+		a += c.Amount()
+	}
+	return a
+}
+
+// Tree arranges the categories in a hierarchy by splitting their names at
+// PathSeparator. Missing intermediate levels are created without events, but
+// with their Metadata from mds (which may be nil).
+// The order of the categories is retained.
+func Tree(cs []Category, mds map[string]Metadata) []*Node {
+	roots := []*Node{}
+	byPath := map[string]*Node{}
+	for _, c := range cs {
+		if c.Uncategorized {
+			roots = append(roots, &Node{Category: c})
+			continue
+		}
+
+		var parent *Node
+		path := ""
+		for _, l := range strings.Split(c.Name, PathSeparator) {
+			if path != "" {
+				path += PathSeparator
+			}
+			path += l
+
+			n, ok := byPath[path]
+			if !ok {
+				n = &Node{Category: Category{Name: path, Metadata: mds[path]}}
+				byPath[path] = n
+				if parent == nil {
+					roots = append(roots, n)
+				} else {
+					parent.Children = append(parent.Children, n)
+				}
+			}
+			parent = n
+		}
+		parent.Events = append(parent.Events, c.Events...)
+		if c.Metadata != (Metadata{}) {
+			parent.Metadata = c.Metadata
+		}
+	}
+	return roots
+}
+
+// SortTree sorts the nodes and the children of each node recursively.
+// Uncategorized nodes always come last.
+func SortTree(ns []*Node, less func(a, b *Node) bool) {
+	sort.SliceStable(ns, func(i, j int) bool {
+		if ns[i].Uncategorized != ns[j].Uncategorized {
+			return ns[j].Uncategorized
+		}
+		return less(ns[i], ns[j])
+	})
+	for _, n := range ns {
+		SortTree(n.Children, less)
+	}
+}
+
+// ByName orders nodes alphabetically.
+func ByName(a, b *Node) bool {
+	return a.Name < b.Name
+}
+
+// ByDuration returns an order of the nodes and their descendants by the total
+// duration of all their events in descending order. The totals are computed
+// in advance, so the nodes must not be changed before sorting.
+func ByDuration(ns []*Node) func(a, b *Node) bool {
+	totals := map[*Node]time.Duration{}
+	var total func(n *Node) time.Duration
+	total = func(n *Node) time.Duration {
+		d := n.Events.Duration()
+		for _, c := range n.Children {
+			d += total(c)
+		}
+		totals[n] = d
+		return d
+	}
+	for _, n := range ns {
+		total(n)
+	}
+
+	return func(a, b *Node) bool {
+		return totals[a] > totals[b]
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cat_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/abc-inc/cal2cat/cat"
+	"github.com/abc-inc/cal2cat/event"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestTree(t *testing.T) {
+	today := time.Now().Truncate(24 * time.Hour)
+	ev := func(h int, summary string) event.Wrapper {
+		start := today.Add(time.Duration(h) * time.Hour)
+		return event.NewSimpleEvent(start, start.Add(time.Duration(h)*time.Hour), summary)
+	}
+
+	ns := Tree([]Category{
+		{Name: "Info", Events: event.Events{ev(1, "JF")}},
+		{Name: "Project ABC", Events: event.Events{ev(5, "ABC")}},
+		{Name: "Project ABC/Review", Events: event.Events{ev(4, "Review")}},
+		{Name: "Project ABC/Testing/Unit", Events: event.Events{ev(2, "Unit")}},
+		{Name: Uncategorized, Events: event.Events{ev(9, "?")}, Uncategorized: true},
+	}, nil)
+	Equal(t, 3, len(ns))
+	Equal(t, "Info", ns[0].Name)
+	abc := ns[1]
+	Equal(t, "Project ABC", abc.Label())
+	Equal(t, 1, len(abc.Events))
+	Equal(t, 2, len(abc.Children))
+	Equal(t, "Testing", abc.Children[1].Label())
+	Empty(t, abc.Children[1].Events)
+	Equal(t, "Project ABC/Testing/Unit", abc.Children[1].Children[0].Name)
+
+	es := abc.AllEvents()
+	Equal(t, []string{"Unit", "Review", "ABC"}, []string{es[0].Summary(), es[1].Summary(), es[2].Summary()})
+	Equal(t, 11*time.Hour, es.Duration())
+
+	SortTree(ns, ByDuration(ns))
+	Equal(t, "Project ABC", ns[0].Name)
+	Equal(t, "Project ABC/Review", ns[0].Children[0].Name)
+	Equal(t, "Info", ns[1].Name)
+	True(t, ns[2].Uncategorized)
+
+	SortTree(ns, ByName)
+	Equal(t, "Info", ns[0].Name)
+	Equal(t, "Project ABC", ns[1].Name)
+	Equal(t, "Project ABC/Review", ns[1].Children[0].Name)
+	Equal(t, "Project ABC/Testing", ns[1].Children[1].Name)
+	True(t, ns[2].Uncategorized)
+}
+
+func TestNode_Amount(t *testing.T) {
+	today := time.Now().Truncate(24 * time.Hour)
+	ev := func(h int) event.Wrapper {
+		return event.NewSimpleEvent(today, today.Add(time.Duration(h)*time.Hour), "x")
+	}
+
+	ns := Tree([]Category{
+		{Name: "ABC/Dev", Events: event.Events{ev(2)}, Metadata: Metadata{Billable: true, Rate: 100}},
+		{Name: "ABC/Ops", Events: event.Events{ev(1)}, Metadata: Metadata{Billable: true, Rate: 50}},
+		{Name: "ABC/Internal", Events: event.Events{ev(4)}},
+	}, map[string]Metadata{"ABC": {BookingCode: "P-1", Billable: true, Rate: 10}})
+	Equal(t, 1, len(ns))
+	Equal(t, "P-1", ns[0].Metadata.BookingCode)
+	Equal(t, 100.0, ns[0].Children[0].Metadata.Rate)
+	Equal(t, 250.0, ns[0].Amount())
+	Equal(t, 50.0, ns[0].Children[1].Amount())
+}
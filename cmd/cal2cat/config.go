@@ -0,0 +1,382 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/cal2cat/cat"
+	"github.com/abc-inc/cal2cat/internal/history"
+	"github.com/adrg/xdg"
+	"gopkg.in/ini.v1"
+)
+
+// defMinConfidence is the default minimum confidence of guessed categories.
+const defMinConfidence = 0.6
+
+func readConfig(cfgPath string) *ini.File {
+	// only '=' separates keys from values, because patterns may contain ':'
+	opts := ini.LoadOptions{Loose: true, KeyValueDelimiters: "="}
+	// later sources take precedence over the defaults
+	cfg, err := ini.LoadSources(opts, defIni, cfgPath, "cal2booking.ini")
+	if err != nil {
+		log.Fatalf("cannot read config file from %s: %v", cfgPath, err)
+	}
+
+	cfgFile, err := os.Create(cfgPath)
+	if err != nil {
+		log.Fatalf("cannot create config file %s: %v", cfgPath, err)
+	}
+	defer cfgFile.Close()
+	_, _ = cfg.WriteTo(cfgFile)
+
+	return cfg
+}
+
+// newMapping creates the Mapping from the sections normalize, rewrite,
+// mapping, classifier, categories and aliases. Each rule remembers its line
+// number in the config file.
+//
+// For each calendar, the rules of the sections mapping.<calendar> and the
+// category from the section calendar.<calendar> precede the global rules.
+// Sections with a date range like mapping:2021-01-01..2021-03-31 contain rules
+// in force for events starting within that range. They precede the section
+// without date range.
+//
+// If the history h is not nil, the classifier is trained with it.
+func newMapping(cfg *ini.File, cfgPath string, h *history.History) cat.Mapping {
+	opts := ruleOptions(cfg)
+	mp := cat.Mapping{Normalizer: newNormalizer(cfg), Metadata: newMetadata(cfg), Aliases: newAliases(cfg)}
+	for _, cn := range cfg.Section("calendars").KeyStrings() {
+		mp.Rules = append(mp.Rules, newRules(cfg, cfgPath, cn, opts)...)
+		if k := cfg.Section("calendar." + cn).Key("category"); k.String() != "" {
+			r, err := cat.NewRule("*", k.String(), keyLines(cfgPath, "calendar."+cn)[k.Name()], opts...)
+			if err != nil {
+				log.Fatalf("invalid category of calendar %s: %v", cn, err)
+			}
+			r.Calendar = cn
+			mp.Rules = append(mp.Rules, r)
+		}
+	}
+	mp.Rules = append(mp.Rules, newRules(cfg, cfgPath, "", opts)...)
+
+	if h != nil {
+		nb := cat.NewNaiveBayes()
+		for _, e := range h.Entries() {
+			nb.Train(mp.Normalizer(e.Summary), e.Category)
+		}
+		mp.Classifier = nb
+		mp.MinConfidence = cfg.Section("classifier").Key("minConfidence").MustFloat64(defMinConfidence)
+		if mp.MinConfidence < 0 || mp.MinConfidence > 1 {
+			log.Fatalf("invalid value minConfidence=%v: must be between 0 and 1", mp.MinConfidence)
+		}
+	}
+
+	lintCategories(mp)
+	return mp
+}
+
+// ruleOptions returns the Options for compiling the patterns of rules
+// according to the section settings.
+func ruleOptions(cfg *ini.File) []cat.Option {
+	t := cfg.Section("settings").Key("fuzzyThreshold").MustFloat64(cat.DefaultFuzzyThreshold)
+	if t < 0 || t > 1 {
+		log.Fatalf("invalid value fuzzyThreshold=%v: must be between 0 and 1", t)
+	}
+	return []cat.Option{cat.WithFuzzyThreshold(t)}
+}
+
+// newAliases creates the aliases from the section aliases, where each key is
+// a canonical category name and the value is a comma-separated list of its
+// aliases.
+func newAliases(cfg *ini.File) map[string]string {
+	as := map[string]string{}
+	for _, k := range cfg.Section("aliases").Keys() {
+		for _, a := range k.Strings(",") {
+			if c, ok := as[a]; ok && c != k.Name() {
+				log.Fatalf("alias %s refers to %s and %s", a, c, k.Name())
+			}
+			as[a] = k.Name()
+		}
+	}
+	return as
+}
+
+// lintCategories warns about category names, which differ only by case or
+// punctuation and should probably be declared as aliases.
+func lintCategories(mp cat.Mapping) {
+	for _, g := range cat.SimilarNames(mp.Categories()) {
+		log.Printf("warning: categories %q differ only by case or punctuation, consider declaring aliases", g)
+	}
+}
+
+// newRules creates the rules of all mapping sections, which apply to events of
+// the named calendar or to all events if calendar is empty. Rules of sections
+// with date range come first.
+func newRules(cfg *ini.File, cfgPath, calendar string, opts []cat.Option) []cat.Rule {
+	name := "mapping"
+	if calendar != "" {
+		name += "." + calendar
+	}
+
+	secs := []*ini.Section{}
+	for _, sec := range cfg.Sections() {
+		if strings.HasPrefix(sec.Name(), name+":") {
+			secs = append(secs, sec)
+		}
+	}
+	if sec, err := cfg.GetSection(name); err == nil {
+		secs = append(secs, sec)
+	}
+
+	rs := []cat.Rule{}
+	for _, sec := range secs {
+		var from, until time.Time
+		if _, dates, ok := strings.Cut(sec.Name(), ":"); ok {
+			var err error
+			if from, until, err = cat.ParseDateRange(dates); err != nil {
+				log.Fatalf("invalid section %s: %v", sec.Name(), err)
+			}
+		}
+
+		lines := keyLines(cfgPath, sec.Name())
+		for _, k := range sec.Keys() {
+			r, err := cat.NewRule(k.Name(), k.Value(), lines[k.Name()], opts...)
+			if err != nil {
+				log.Fatalf("invalid mapping %s=%s in section %s: %v", k.Name(), k.Value(), sec.Name(), err)
+			}
+			r.Calendar, r.ValidFrom, r.ValidUntil = calendar, from, until
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+// newMetadata creates the metadata of all categories from the section
+// categories, where each key consists of the category name and the attribute,
+// e.g., "Project ABC.code".
+func newMetadata(cfg *ini.File) map[string]cat.Metadata {
+	mds := map[string]cat.Metadata{}
+	for _, k := range cfg.Section("categories").Keys() {
+		i := strings.LastIndex(k.Name(), ".")
+		if i < 0 {
+			log.Fatalf("invalid category attribute %s: expected <category>.<attribute>", k.Name())
+		}
+
+		var err error
+		cn, md := k.Name()[:i], mds[k.Name()[:i]]
+		switch attr := k.Name()[i+1:]; attr {
+		case "code":
+			md.BookingCode = k.String()
+		case "billable":
+			md.Billable, err = k.Bool()
+		case "rate":
+			md.Rate, err = k.Float64()
+		case "color":
+			md.Color = k.String()
+		case "description":
+			md.Description = k.String()
+		case "priority":
+			md.Priority, err = k.Int()
+		default:
+			log.Fatalf("unknown attribute %q of category %s", attr, cn)
+		}
+		if err != nil {
+			log.Fatalf("invalid value %s=%s: %v", k.Name(), k.Value(), err)
+		}
+		mds[cn] = md
+	}
+	return mds
+}
+
+// newNormalizer creates a Normalizer, which applies the rewrite rules and
+// cleans up the summary according to the normalize section.
+func newNormalizer(cfg *ini.File) cat.Normalizer {
+	sec := cfg.Section("normalize")
+	ns := []cat.Normalizer{}
+	if sec.Key("removeTags").MustBool(false) {
+		ns = append(ns, cat.RemoveTags)
+	}
+	if sec.Key("removeEmoji").MustBool(false) {
+		ns = append(ns, cat.RemoveEmoji)
+	}
+	if ps := sec.Key("stripPrefixes").Strings(","); len(ps) > 0 {
+		ns = append(ns, cat.NewPrefixStripper(ps...))
+	}
+
+	for _, k := range cfg.Section("rewrite").Keys() {
+		n, err := cat.NewRewriter(k.Name(), k.Value())
+		if err != nil {
+			log.Fatalf("invalid rewrite rule %s=%s: %v", k.Name(), k.Value(), err)
+		}
+		ns = append(ns, n)
+	}
+
+	if sec.Key("collapseWhitespace").MustBool(true) {
+		ns = append(ns, cat.CollapseWhitespace)
+	}
+	return cat.Chain(ns...)
+}
+
+// loadHistory loads the previously categorized events, if the classifier is
+// enabled. Otherwise, it returns nil.
+func loadHistory(cfg *ini.File) *history.History {
+	if !cfg.Section("classifier").Key("enabled").MustBool(false) {
+		return nil
+	}
+
+	path, err := xdg.DataFile("cal2booking/history.json")
+	if err != nil {
+		log.Fatalf("cannot determine history file: %v", err)
+	}
+	h, err := history.Load(path)
+	if err != nil {
+		log.Fatalf("cannot read history from %s: %v", path, err)
+	}
+	return h
+}
+
+// recordHistory adds all events categorized by rules to the history, except
+// for events split across several categories.
+func recordHistory(h *history.History, cs []cat.Category) {
+	for _, c := range cs {
+		if c.Uncategorized {
+			continue
+		}
+		for _, e := range c.Events {
+			switch e.(type) {
+			case *cat.Guess, *cat.Share:
+			default:
+				h.Add(history.Entry{Start: e.StartTime(), Summary: e.Summary(), Category: c.Name})
+			}
+		}
+	}
+	if err := h.Save(); err != nil {
+		log.Fatalf("cannot write history: %v", err)
+	}
+}
+
+// keyLines returns the line numbers of all keys in a section of an INI file.
+func keyLines(path, section string) map[string]int {
+	lines := map[string]int{}
+	f, err := os.Open(path)
+	if err != nil {
+		return lines
+	}
+	defer f.Close()
+
+	cur := ""
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		l := strings.TrimSpace(sc.Text())
+		switch {
+		case l == "" || strings.HasPrefix(l, ";") || strings.HasPrefix(l, "#"):
+		case strings.HasPrefix(l, "[") && strings.HasSuffix(l, "]"):
+			cur = strings.TrimSpace(l[1 : len(l)-1])
+		case cur == section && strings.Contains(l, "="):
+			k := strings.TrimSpace(l[:strings.Index(l, "=")])
+			if len(k) > 1 && (k[0] == '`' || k[0] == '"') && k[len(k)-1] == k[0] {
+				k = k[1 : len(k)-1]
+			}
+			lines[k] = n
+		}
+	}
+	return lines
+}
+
+// appendRule appends the rule to the mapping section of the INI file and
+// returns its line number. All other lines are retained as they are.
+func appendRule(path string, r cat.Rule) (int, error) {
+	rule, err := iniLine(r.Pattern, r.Category)
+	if err != nil {
+		return 0, err
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	nl := "\n"
+	if strings.Contains(string(b), "\r\n") {
+		nl = "\r\n"
+	}
+	var ls []string
+	if len(b) > 0 {
+		ls = strings.Split(strings.TrimRight(string(b), nl), nl)
+	}
+
+	// find the last key of the section, because comments preceding the next
+	// section belong to that section
+	pos, inSec := -1, false
+	for i, l := range ls {
+		l = strings.TrimSpace(l)
+		if strings.HasPrefix(l, "[") && strings.HasSuffix(l, "]") {
+			if inSec {
+				break
+			}
+			if inSec = strings.TrimSpace(l[1:len(l)-1]) == "mapping"; inSec {
+				pos = i
+			}
+		} else if inSec && l != "" && !strings.HasPrefix(l, ";") && !strings.HasPrefix(l, "#") {
+			pos = i
+		}
+	}
+
+	if pos < 0 {
+		ls = append(ls, "", "[mapping]", rule)
+		pos = len(ls) - 1
+	} else {
+		pos++
+		ls = append(ls[:pos], append([]string{rule}, ls[pos:]...)...)
+	}
+
+	err = os.WriteFile(path, []byte(strings.Join(ls, nl)+nl), 0o600)
+	return pos + 1, err
+}
+
+// iniLine formats a key-value pair of an INI file. The key and the value are
+// quoted, if they contain characters, which would otherwise be interpreted as
+// delimiter, comment or quote. Keys and values, which cannot be quoted, are
+// rejected.
+func iniLine(k, v string) (string, error) {
+	if strings.ContainsAny(k+v, "\r\n") {
+		return "", fmt.Errorf("%q = %q must not contain line breaks", k, v)
+	}
+
+	switch {
+	case strings.Contains(k, "`") && strings.Contains(k, `"`):
+		return "", fmt.Errorf("pattern %q must not contain both '`' and '\"'", k)
+	case strings.Contains(k, "`"):
+		k = `"""` + k + `"""`
+	case strings.ContainsAny(k, `="`) || strings.IndexAny(k, ";#[") == 0:
+		k = "`" + k + "`"
+	}
+
+	switch {
+	case strings.Contains(v, "`") && strings.Contains(v, `"`):
+		return "", fmt.Errorf("category %q must not contain both '`' and '\"'", v)
+	case strings.Contains(v, "`"):
+		v = `"""` + v + `"""`
+	case strings.ContainsAny(v, ";#") || strings.HasPrefix(v, `"`):
+		v = "`" + v + "`"
+	}
+	return k + " = " + v, nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abc-inc/cal2cat/cat"
+	. "github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func writeConfig(t *testing.T, s string) string {
+	path := filepath.Join(t.TempDir(), "config.ini")
+	NoError(t, os.WriteFile(path, []byte(s), 0o600))
+	return path
+}
+
+func TestAppendRule(t *testing.T) {
+	path := writeConfig(t, `; settings
+[settings]
+timeFormat = 2006-01-02 15:04
+
+[mapping]
+; Projects
+^ABC = Project ABC
+# Other
+*XYZ* = Project XYZ
+
+; calendars
+[calendars]
+work = work.ics
+`)
+
+	n, err := appendRule(path, cat.Rule{Pattern: "JF*", Category: "Meeting"})
+	NoError(t, err)
+	Equal(t, 10, n)
+
+	b, err := os.ReadFile(path)
+	NoError(t, err)
+	Equal(t, `; settings
+[settings]
+timeFormat = 2006-01-02 15:04
+
+[mapping]
+; Projects
+^ABC = Project ABC
+# Other
+*XYZ* = Project XYZ
+JF* = Meeting
+
+; calendars
+[calendars]
+work = work.ics
+`, string(b))
+}
+
+func TestAppendRule_NewSection(t *testing.T) {
+	path := writeConfig(t, "[settings]\r\ntimeFormat = 15:04\r\n")
+
+	n, err := appendRule(path, cat.Rule{Pattern: "JF*", Category: "Meeting"})
+	NoError(t, err)
+	Equal(t, 5, n)
+
+	b, err := os.ReadFile(path)
+	NoError(t, err)
+	Equal(t, "[settings]\r\ntimeFormat = 15:04\r\n\r\n[mapping]\r\nJF* = Meeting\r\n", string(b))
+}
+
+func TestAppendRule_Quote(t *testing.T) {
+	rs := []cat.Rule{
+		{Pattern: "a=b", Category: "Eq"},
+		{Pattern: `say "hi"`, Category: "Quote"},
+		{Pattern: "`code`", Category: "Backtick"},
+		{Pattern: "; semicolon", Category: "Comment"},
+		{Pattern: "[AB]*", Category: "Section"},
+		{Pattern: "Room:101", Category: "Room #101; 1st floor"},
+		{Pattern: "Team", Category: "`Team` \"A\""},
+	}
+
+	path := writeConfig(t, "")
+	for _, r := range rs[:len(rs)-1] {
+		_, err := appendRule(path, r)
+		NoError(t, err)
+	}
+	_, err := appendRule(path, rs[len(rs)-1])
+	Error(t, err)
+
+	cfg, err := ini.LoadSources(ini.LoadOptions{KeyValueDelimiters: "="}, path)
+	NoError(t, err)
+	ks := cfg.Section("mapping").Keys()
+	Len(t, ks, len(rs)-1)
+	for i, k := range ks {
+		Equal(t, rs[i].Pattern, k.Name())
+		Equal(t, rs[i].Category, k.Value())
+	}
+}
+
+func TestIniLine(t *testing.T) {
+	_, err := iniLine("`a\"", "X")
+	Error(t, err)
+	_, err = iniLine("a", "X\nY")
+	Error(t, err)
+
+	l, err := iniLine("a", "b")
+	NoError(t, err)
+	Equal(t, "a = b", l)
+}
@@ -0,0 +1,155 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/abc-inc/cal2cat/cat"
+	"github.com/abc-inc/cal2cat/event"
+	"github.com/abc-inc/cal2cat/internal/duration"
+	"github.com/adrg/xdg"
+	"github.com/spf13/cobra"
+	"gopkg.in/ini.v1"
+)
+
+// defConflicts is the default strategy of resolving overlapping events.
+const defConflicts = "earliest"
+
+func newConflictsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "conflicts",
+		Short: "List overlapping events.",
+		Long: `conflicts lists all clusters of overlapping events with their categories,
+the overlapping time and the events kept by the conflict resolution strategy.`,
+		Run: conflicts,
+	}
+}
+
+func conflicts(cmd *cobra.Command, args []string) {
+	cfgPath, _ := xdg.ConfigFile("cal2booking/config.ini")
+	cfg := readConfig(cfgPath)
+	timeFmt := cfg.Section("settings").Key("timeFormat").MustString(defTimeFmt)
+	durFmt := cfg.Section("settings").Key("durationFormat").MustString(defDurFmt)
+
+	rangeStart, rangeEnd := timeRange(cmd)
+	mp := newMapping(cfg, cfgPath, loadHistory(cfg))
+	es := loadEvents(cmd, cfg, mp, rangeStart, rangeEnd)
+	strategy := conflictStrategy(cmd, cfg)
+	fmt.Printf("Overlapping events from %s until %s (strategy: %s)\n",
+		rangeStart.Format(timeFmt), rangeEnd.Format(timeFmt), strategy)
+
+	// resolved events may be wrapped, e.g., in a Portion, so they are looked up
+	// by the original event
+	resolved := map[event.Wrapper]event.Wrapper{}
+	for _, e := range resolve(strategy, cfg, mp, es) {
+		resolved[event.Original(e)] = e
+	}
+
+	for _, cl := range es.Clusters() {
+		// overlapping events cover a single interval
+		span := cl.Intervals()[0]
+		fmt.Println(strings.Repeat("-", 80))
+		fmt.Printf("%s - %s (%d events, overlap %s)\n",
+			span.Start.Format(timeFmt), span.End.Format(timeFmt), len(cl),
+			duration.Format(cl.Overlap(), durFmt))
+		for _, e := range cl {
+			status, d := "dropped", duration.Format(e.Duration(), durFmt)
+			if r, ok := resolved[event.Original(e)]; ok && r == e {
+				status = "kept"
+			} else if ok {
+				status = "partial"
+				d = duration.Format(r.Duration(), durFmt) + " of " + d
+			}
+
+			cn := mp.Category(e)
+			if cn == "" {
+				cn = cat.Uncategorized
+			}
+			fmt.Printf("  %-8s %v %s (%s) -> %s", status, e.StartTime().Format(timeFmt), e.Summary(), d, cn)
+			if e.Calendar() != "" {
+				fmt.Printf(" [%s]", e.Calendar())
+			}
+			fmt.Println()
+		}
+	}
+}
+
+// resolveConflicts resolves overlapping events using the strategy given by the
+// flag conflicts or the setting of the same name.
+func resolveConflicts(cmd *cobra.Command, cfg *ini.File, mp cat.Mapping, es event.Events) event.Events {
+	return resolve(conflictStrategy(cmd, cfg), cfg, mp, es)
+}
+
+// conflictStrategy returns the strategy given by the flag conflicts or the
+// setting of the same name.
+func conflictStrategy(cmd *cobra.Command, cfg *ini.File) string {
+	if strategy, _ := cmd.Flags().GetString("conflicts"); strategy != "" {
+		return strategy
+	}
+	return cfg.Section("settings").Key("conflicts").MustString(defConflicts)
+}
+
+// resolve resolves overlapping events using one of the strategies:
+//
+//	earliest  keep the earliest event
+//	longest   keep the longest event
+//	calendar  keep the event of the calendar with the highest priority
+//	category  keep the event of the category with the highest priority
+//	split     split overlapping time equally between the events
+//	busy      count overlapping time once for the event with the highest
+//	          category priority, calendar priority or the earliest one
+func resolve(strategy string, cfg *ini.File, mp cat.Mapping, es event.Events) event.Events {
+	calPrios := map[string]int{}
+	for _, cn := range cfg.Section("calendars").KeyStrings() {
+		if sec, err := cfg.GetSection("calendar." + cn); err == nil {
+			calPrios[cn] = sec.Key("priority").MustInt(0)
+		}
+	}
+	calPrio := func(e event.Wrapper) int {
+		return calPrios[e.Calendar()]
+	}
+	catPrios := map[event.Wrapper]int{}
+	if strategy == "category" || strategy == "busy" {
+		// categorize each event once instead of on each comparison
+		for _, e := range es {
+			catPrios[e] = mp.Metadata[mp.Category(e)].Priority
+		}
+	}
+	catPrio := func(e event.Wrapper) int {
+		return catPrios[e]
+	}
+
+	var kept event.Events
+	switch strategy {
+	case "earliest":
+		kept, _ = es.Keep(event.Earliest)
+	case "longest":
+		kept, _ = es.Keep(event.Longest)
+	case "calendar":
+		kept, _ = es.Keep(event.ByPriority(calPrio))
+	case "category":
+		kept, _ = es.Keep(event.ByPriority(catPrio))
+	case "split":
+		kept = es.Split()
+	case "busy":
+		kept = es.Busy(event.ByPriority(catPrio, calPrio))
+	default:
+		log.Fatalf("invalid conflict resolution strategy %q", strategy)
+	}
+	return kept
+}
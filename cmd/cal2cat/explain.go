@@ -0,0 +1,107 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/cal2cat/cat"
+	"github.com/abc-inc/cal2cat/event"
+	"github.com/adrg/xdg"
+	"github.com/spf13/cobra"
+)
+
+func newExplainCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain [summary...]",
+		Short: "Show which mapping rule categorizes an event.",
+		Long: `explain checks summaries against the rules in the mapping section in the
+given order and shows the rules tried and the rule that matched.
+If no summary is given, all events in the time range are explained.`,
+		Run: explain,
+	}
+	cmd.Flags().String("at", "", "start time of the summaries (default now)")
+	cmd.Flags().Duration("duration", time.Hour, "duration of the summaries")
+	return cmd
+}
+
+func explain(cmd *cobra.Command, args []string) {
+	cfgPath, _ := xdg.ConfigFile("cal2booking/config.ini")
+	cfg := readConfig(cfgPath)
+	mp := newMapping(cfg, cfgPath, loadHistory(cfg))
+	timeFmt := cfg.Section("settings").Key("timeFormat").MustString(defTimeFmt)
+
+	if len(args) > 0 {
+		start := time.Now()
+		if at, _ := cmd.Flags().GetString("at"); at != "" {
+			var err error
+			if start, err = time.ParseInLocation(timeFmt, at, time.Local); err != nil {
+				log.Fatalf("cannot parse time %s: %v", at, err)
+			}
+		}
+		d, _ := cmd.Flags().GetDuration("duration")
+		for _, s := range args {
+			printExplanation(mp.Explain(event.NewSimpleEvent(start, start.Add(d), s)))
+		}
+		return
+	}
+
+	rangeStart, rangeEnd := timeRange(cmd)
+	for _, e := range resolveConflicts(cmd, cfg, mp, loadEvents(cmd, cfg, mp, rangeStart, rangeEnd)) {
+		fmt.Printf("%v ", e.StartTime().Format(timeFmt))
+		printExplanation(mp.Explain(event.Original(e)))
+	}
+}
+
+// printExplanation prints the summary followed by all rules checked.
+func printExplanation(x cat.Explanation) {
+	fmt.Printf("%q\n", x.Summary)
+	if x.Normalized != x.Summary {
+		fmt.Printf("    normalized: %q\n", x.Normalized)
+	}
+	for _, r := range x.Tried {
+		fmt.Printf("    tried       %s\n", formatRule(r))
+	}
+	switch {
+	case x.Match != nil && x.Match.Fuzzy():
+		fmt.Printf("    matched     %s (%.0f%% similarity)\n", formatRule(*x.Match), 100*x.Similarity)
+	case x.Match != nil:
+		fmt.Printf("    matched     %s\n", formatRule(*x.Match))
+	case x.Guess != "":
+		fmt.Printf("    guessed     %s (%.0f%% confidence)\n", x.Guess, 100*x.Confidence)
+	default:
+		fmt.Println("    no rule matched")
+	}
+}
+
+// formatRule returns the rule in config file syntax, prefixed by its line and
+// followed by its scope, if any.
+func formatRule(r cat.Rule) string {
+	scope := []string{}
+	if r.Calendar != "" {
+		scope = append(scope, "calendar "+r.Calendar)
+	}
+	if !r.ValidFrom.IsZero() || !r.ValidUntil.IsZero() {
+		scope = append(scope, "valid "+cat.FormatDateRange(r.ValidFrom, r.ValidUntil))
+	}
+
+	if len(scope) == 0 {
+		return fmt.Sprintf("line %3d: %s", r.Line, r)
+	}
+	return fmt.Sprintf("line %3d: %s (%s)", r.Line, r, strings.Join(scope, ", "))
+}
@@ -0,0 +1,211 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/abc-inc/cal2cat/cat"
+	"github.com/abc-inc/cal2cat/event"
+)
+
+// errQuit indicates that the user wants to stop categorizing.
+var errQuit = errors.New("quit")
+
+// prompter reads answers from the user.
+type prompter struct {
+	sc *bufio.Scanner
+}
+
+// ask prints the question and returns the trimmed answer.
+func (p prompter) ask(question string) (string, error) {
+	fmt.Print(question)
+	if !p.sc.Scan() {
+		if err := p.sc.Err(); err != nil {
+			return "", err
+		}
+		return "", errQuit
+	}
+	return strings.TrimSpace(p.sc.Text()), nil
+}
+
+// categorizeInteractively walks through the summaries of all uncategorized
+// events, lets the user pick a category and a pattern and appends the new rule
+// to the config file. The new rules are added to the Mapping as well.
+// The Options are used to compile the patterns.
+func categorizeInteractively(r io.Reader, cfgPath string, mp *cat.Mapping, es event.Events, opts []cat.Option) {
+	p := prompter{bufio.NewScanner(r)}
+	for _, e := range uncategorized(*mp, es) {
+		if mp.Explain(e).Match != nil {
+			// already covered by a rule added in the meantime
+			continue
+		}
+
+		fmt.Println(strings.Repeat("-", 80))
+		fmt.Printf("Uncategorized: %q\n", e.Summary())
+		rule, err := askRule(p, *mp, e, opts)
+		if errors.Is(err, errQuit) {
+			return
+		} else if err != nil {
+			log.Fatalf("cannot read input: %v", err)
+		} else if rule == nil {
+			continue
+		}
+
+		if rule.Line, err = appendRule(cfgPath, *rule); err != nil {
+			log.Fatalf("cannot write config file %s: %v", cfgPath, err)
+		}
+		mp.Rules = append(mp.Rules, *rule)
+		fmt.Printf("Added %s\n", formatRule(*rule))
+	}
+}
+
+// uncategorized returns the first event of each distinct summary, which is
+// not matched by any rule, in chronological order.
+func uncategorized(mp cat.Mapping, es event.Events) event.Events {
+	seen := map[string]interface{}{}
+	res := event.Events{}
+	for _, e := range es {
+		if _, ok := seen[e.Summary()]; ok || mp.Explain(e).Match != nil {
+			continue
+		}
+		seen[e.Summary()] = nil
+		res = append(res, e)
+	}
+	return res
+}
+
+// askRule asks for a category and a pattern and returns the new rule or nil,
+// if the event should be skipped.
+func askRule(p prompter, mp cat.Mapping, e event.Wrapper, opts []cat.Option) (*cat.Rule, error) {
+	cn, err := askCategory(p, mp.Categories())
+	if err != nil || cn == "" {
+		return nil, err
+	}
+
+	normalized := mp.Explain(e).Normalized
+	ps := cat.ProposePatterns(normalized)
+	for {
+		fmt.Println("Pattern:")
+		for i, pat := range ps {
+			fmt.Printf("  %d) %s\n", i+1, pat)
+		}
+		a, err := p.ask(`Enter a number or a custom pattern (empty to skip, "q" to quit): `)
+		if err != nil || a == "" {
+			return nil, err
+		} else if a == "q" {
+			return nil, errQuit
+		}
+		if i, err := strconv.Atoi(a); err == nil && i >= 1 && i <= len(ps) {
+			a = ps[i-1]
+		}
+
+		r, err := cat.NewRule(a, cn, 0, opts...)
+		if err != nil {
+			fmt.Printf("Invalid pattern %q: %v\n", a, err)
+			continue
+		}
+		if !r.MatchEvent(e, normalized) {
+			fmt.Printf("Pattern %q does not match %q\n", a, e.Summary())
+			continue
+		}
+		if _, err := iniLine(r.Pattern, r.Category); err != nil {
+			fmt.Printf("Invalid rule: %v\n", err)
+			continue
+		}
+
+		a, err = p.ask(fmt.Sprintf("Append %q to section mapping? [Y/n] ", r.String()))
+		if err != nil {
+			return nil, err
+		} else if a == "" || strings.EqualFold(a, "y") {
+			return &r, nil
+		}
+	}
+}
+
+// askCategory lets the user pick one of the existing categories, search them,
+// or enter a new one. It returns an empty string if the user skips.
+func askCategory(p prompter, cns []string) (string, error) {
+	matches := cns
+	for {
+		fmt.Println("Category:")
+		for i, cn := range matches {
+			fmt.Printf("  %d) %s\n", i+1, cn)
+		}
+		a, err := p.ask(`Enter a number, text to search, "+<name>" for a new category (empty to skip, "q" to quit): `)
+		switch {
+		case err != nil || a == "":
+			return "", err
+		case a == "q":
+			return "", errQuit
+		case strings.HasPrefix(a, "+"):
+			if cn := strings.TrimSpace(a[1:]); cn != "" {
+				return cn, nil
+			}
+		default:
+			if i, err := strconv.Atoi(a); err == nil && i >= 1 && i <= len(matches) {
+				return matches[i-1], nil
+			}
+			if matches = fuzzyFind(a, cns); len(matches) == 0 {
+				fmt.Printf("No category matches %q\n", a)
+				matches = cns
+			}
+		}
+	}
+}
+
+// fuzzyFind returns the strings containing all characters of the query in the
+// same order (ignoring case). Better matches, i.e., with fewer characters in
+// between, come first.
+func fuzzyFind(query string, ss []string) []string {
+	q := []rune(strings.ToLower(query))
+	scores := map[string]int{}
+	res := []string{}
+	for _, s := range ss {
+		if score, ok := fuzzyScore(q, strings.ToLower(s)); ok {
+			scores[s] = score
+			res = append(res, s)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return scores[res[i]] < scores[res[j]]
+	})
+	return res
+}
+
+// fuzzyScore returns the number of characters skipped while matching the
+// query as a subsequence of s, and whether s contains the query at all.
+func fuzzyScore(q []rune, s string) (int, bool) {
+	score, qi := 0, 0
+	for _, r := range s {
+		if qi == len(q) {
+			break
+		}
+		if r == q[qi] {
+			qi++
+		} else if qi > 0 {
+			score++
+		}
+	}
+	return score, qi == len(q)
+}
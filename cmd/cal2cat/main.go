@@ -18,8 +18,6 @@ import (
 	_ "embed"
 	"fmt"
 	"log"
-	"os"
-	"strings"
 	"time"
 
 	"github.com/abc-inc/cal2cat/cal"
@@ -33,6 +31,7 @@ import (
 
 const defDurFmt = "hours"
 const defTimeFmt = "2006-01-02 15:04"
+const defDateFmt = "Mon 2006-01-02"
 
 //go:embed default.ini
 var defIni []byte
@@ -60,8 +59,21 @@ func main() {
   month and the first day of the year, respectively.`,
 	}
 
-	rootCmd.Flags().StringP("end", "e", "-0cw", "end time")
-	rootCmd.Flags().StringP("start", "s", "-1cw", "start time")
+	rootCmd.PersistentFlags().StringP("end", "e", "-0cw", "end time")
+	rootCmd.PersistentFlags().StringP("start", "s", "-1cw", "start time")
+	rootCmd.PersistentFlags().Bool("clip", false, "clip events to the time range instead of omitting them (default from config)")
+	rootCmd.PersistentFlags().Duration("min-duration", 0, "ignore events shorter than this (default from config)")
+	rootCmd.PersistentFlags().Duration("max-duration", 0, "ignore events longer than this (default from config)")
+	rootCmd.PersistentFlags().StringArray("exclude", nil, "ignore events with summaries matching the pattern")
+	rootCmd.PersistentFlags().String("conflicts", "", "strategy of resolving overlapping events (default from config)")
+	rootCmd.Flags().Bool("explain", false, "show the mapping rule of each event")
+	rootCmd.Flags().Bool("strict", false, "fail if any event is uncategorized")
+	rootCmd.Flags().Bool("clip-hours", false, "clip events to the working hours (default from config)")
+	rootCmd.Flags().Bool("daily", false, "show the duration of each category per day")
+	rootCmd.Flags().Int("depth", 0, "number of category levels to show (0 for all)")
+	rootCmd.Flags().String("sort", "name", "order of categories within each level (name or duration)")
+	rootCmd.Flags().BoolP("interactive", "i", false, "add mapping rules for uncategorized events")
+	rootCmd.AddCommand(newConflictsCmd(), newExplainCmd(), newSuggestCmd())
 	cobra.CheckErr(rootCmd.Execute())
 }
 
@@ -72,60 +84,156 @@ func execute(cmd *cobra.Command, args []string) {
 	timeFmt := cfg.Section("settings").Key("timeFormat").MustString(defTimeFmt)
 	durFmt := cfg.Section("settings").Key("durationFormat").MustString(defDurFmt)
 
-	today := time.Now().UTC().Truncate(24 * time.Hour)
-	fStart, _ := cmd.Flags().GetString("start")
-	rangeStart := duration.Calc(today, fStart)
-	fEnd, _ := cmd.Flags().GetString("end")
-	rangeEnd := duration.Calc(today, fEnd)
+	rangeStart, rangeEnd := timeRange(cmd)
 	fmt.Printf("Categorizing events from %s until %s\n",
 		rangeStart.Format(timeFmt), rangeEnd.Format(timeFmt))
 
-	ms := []cat.Mapper{}
-	sec := cfg.Section("mapping")
-	for _, k := range sec.Keys() {
-		ms = append(ms, cat.NewMapper(k.Name(), k.Value()))
+	h := loadHistory(cfg)
+	mp := newMapping(cfg, cfgPath, h)
+	sched := newSchedule(cfg, rangeStart, rangeEnd)
+	es := loadEvents(cmd, cfg, mp, rangeStart, rangeEnd)
+	if cfg.Section("workingHours").Key("convertAllDay").MustBool(false) {
+		if sched.Empty() {
+			log.Fatalf("cannot convert all-day events to working time: section workingHours defines no working hours")
+		}
+		es = sched.ConvertAllDay(es, time.Local)
 	}
+	es = resolveConflicts(cmd, cfg, mp, es)
 
-	ps := []string{}
-	sec, _ = cfg.GetSection("calendars")
-	for _, k := range sec.Keys() {
-		ps = append(ps, k.Value())
+	var out event.Events
+	if clipToHours(cmd, cfg) {
+		if sched.Empty() {
+			log.Fatalf("cannot clip events to working hours: section workingHours defines no working hours")
+		}
+		es, out = sched.Clip(es, time.Local)
 	}
 
-	es := cal.Load(ps...)
-	es = es.Filter(event.NewRangeFilter(rangeStart, rangeEnd))
-	es = es.Filter(event.NewEventFilter(es.Conflicts().Events()).Not())
+	if interactive, _ := cmd.Flags().GetBool("interactive"); interactive {
+		categorizeInteractively(cmd.InOrStdin(), cfgPath, &mp, es, ruleOptions(cfg))
+	}
+
+	cs := mp.Map(es)
+	if h != nil {
+		recordHistory(h, cs)
+	}
 
-	cs := cat.Map(es, ms)
-	if len(cs) == 0 {
+	var gs event.Events
+	if !sched.Empty() {
+		gs = gaps(sched, es, rangeStart, rangeEnd)
+		if fill := cfg.Section("workingHours").Key("fill").String(); fill != "" {
+			cs, gs = addEvents(mp, cs, fill, gs), nil
+		}
+	}
+	if overtime := cfg.Section("workingHours").Key("overtime").String(); overtime != "" && len(out) > 0 {
+		cs, out = addEvents(mp, cs, overtime, out), nil
+	}
+	if len(cs) == 0 && len(gs) == 0 && len(out) == 0 {
 		return
 	}
 
-	for _, c := range cs {
-		fmt.Println(strings.Repeat("-", 80))
-		fmt.Printf("%s (%d events - %s)\n", c.Name, len(c.Events),
-			duration.Format(c.Events.Duration(), durFmt))
+	ns := cat.Tree(cs, mp.Metadata)
+	switch sortBy, _ := cmd.Flags().GetString("sort"); sortBy {
+	case "name":
+		cat.SortTree(ns, cat.ByName)
+	case "duration":
+		cat.SortTree(ns, cat.ByDuration(ns))
+	default:
+		log.Fatalf("invalid sort order %q", sortBy)
+	}
 
-		for _, e := range c.Events {
-			fmt.Printf("%v %s (%s)\n",
-				e.StartTime().Format(timeFmt), e.Summary(),
-				duration.Format(e.Duration(), durFmt))
-		}
+	r := report{mp: mp, timeFmt: timeFmt, durFmt: durFmt, sched: sched}
+	r.depth, _ = cmd.Flags().GetInt("depth")
+	r.explain, _ = cmd.Flags().GetBool("explain")
+	r.print(ns)
+	if len(out) > 0 {
+		r.printOutOfHours(out)
+	}
+	if len(gs) > 0 {
+		printGaps(gs, timeFmt, durFmt)
+	}
+	if daily, _ := cmd.Flags().GetBool("daily"); daily {
+		r.dateFmt = cfg.Section("settings").Key("dateFormat").MustString(defDateFmt)
+		r.printDaily(cs, rangeStart, rangeEnd)
+	}
+
+	uncategorized := 0
+	if len(cs) > 0 && cs[len(cs)-1].Uncategorized {
+		uncategorized = len(cs[len(cs)-1].Events)
 	}
+	if strict, _ := cmd.Flags().GetBool("strict"); strict && uncategorized > 0 {
+		log.Fatalf("%d events are uncategorized", uncategorized)
+	}
+}
+
+// timeRange returns the time range given by the flags start and end.
+func timeRange(cmd *cobra.Command) (start, end time.Time) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	fStart, _ := cmd.Flags().GetString("start")
+	fEnd, _ := cmd.Flags().GetString("end")
+	return duration.Calc(today, fStart), duration.Calc(today, fEnd)
+}
+
+// loadEvents loads the configured calendars and returns all events within the
+// given range, which are not excluded by the duration or summary filters.
+// Events extending beyond the range are clipped to it, if requested.
+func loadEvents(cmd *cobra.Command, cfg *ini.File, mp cat.Mapping, start, end time.Time) event.Events {
+	srcs := []cal.Source{}
+	sec, _ := cfg.GetSection("calendars")
+	for _, k := range sec.Keys() {
+		srcs = append(srcs, cal.Source{Name: k.Name(), Path: k.Value()})
+	}
+
+	es := cal.Load(srcs...).Filter(newExcludeFilter(cmd, cfg, mp).Not())
+	if clipToRange(cmd, cfg) {
+		return es.Clip(start, end)
+	}
+	return es.Filter(event.NewRangeFilter(start, end))
 }
 
-func readConfig(cfgPath string) *ini.File {
-	cfg, err := ini.LooseLoad("cal2booking.ini", cfgPath, defIni)
-	if err != nil {
-		log.Fatalf("cannot read config file from %s: %v", cfgPath, err)
+// newExcludeFilter creates a Filter, which matches events shorter than
+// minDuration, longer than maxDuration or with a (normalized) summary
+// matching any pattern of the section exclude. The flags of the same names
+// take precedence over the settings. Additional patterns are given by the
+// flag exclude.
+func newExcludeFilter(cmd *cobra.Command, cfg *ini.File, mp cat.Mapping) event.Filter {
+	shortest := cfg.Section("settings").Key("minDuration").MustDuration(0)
+	if cmd.Flags().Changed("min-duration") {
+		shortest, _ = cmd.Flags().GetDuration("min-duration")
+	}
+	longest := cfg.Section("settings").Key("maxDuration").MustDuration(0)
+	if cmd.Flags().Changed("max-duration") {
+		longest, _ = cmd.Flags().GetDuration("max-duration")
+	}
+	if longest <= 0 {
+		longest = time.Duration(1<<63 - 1)
 	}
 
-	cfgFile, err := os.Create(cfgPath)
-	if err != nil {
-		log.Fatalf("cannot create config file %s: %v", cfgPath, err)
+	patterns := cfg.Section("exclude").KeyStrings()
+	fPatterns, _ := cmd.Flags().GetStringArray("exclude")
+	opts := ruleOptions(cfg)
+	ms := []func(string) bool{}
+	for _, p := range append(patterns, fPatterns...) {
+		m, err := cat.NewMatcher(p, opts...)
+		if err != nil {
+			log.Fatalf("invalid exclude pattern %s: %v", p, err)
+		}
+		ms = append(ms, func(s string) bool {
+			return m(mp.Normalizer(s))
+		})
 	}
-	defer cfgFile.Close()
-	_, _ = cfg.WriteTo(cfgFile)
 
-	return cfg
+	return event.Any(
+		event.NewDurationFilter(shortest, longest).Not(),
+		event.NewSummaryFilter(ms...),
+	)
+}
+
+// clipToRange returns whether events are clipped to the time range as given by
+// the flag clip or the setting of the same name.
+func clipToRange(cmd *cobra.Command, cfg *ini.File) bool {
+	if cmd.Flags().Changed("clip") {
+		clip, _ := cmd.Flags().GetBool("clip")
+		return clip
+	}
+	return cfg.Section("settings").Key("clip").MustBool(false)
 }
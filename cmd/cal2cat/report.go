@@ -0,0 +1,191 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/cal2cat/cat"
+	"github.com/abc-inc/cal2cat/event"
+	"github.com/abc-inc/cal2cat/internal/duration"
+	"github.com/abc-inc/cal2cat/work"
+)
+
+// report prints categories and their events.
+type report struct {
+	mp      cat.Mapping
+	timeFmt string
+	durFmt  string
+	// depth is the number of category levels to show, 0 shows all levels
+	depth   int
+	explain bool
+	dateFmt string
+	// sched provides the daily targets and holidays of the daily breakdown
+	sched work.Schedule
+}
+
+// print prints the category tree. Each level shows the totals including all
+// sub-categories. Levels below depth are folded into their ancestor.
+func (r report) print(ns []*cat.Node) {
+	for _, n := range ns {
+		fmt.Println(strings.Repeat("-", 80))
+		r.printNode(n, 1)
+	}
+}
+
+// printOutOfHours lists the time of events outside the working hours.
+func (r report) printOutOfHours(es event.Events) {
+	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("Out of working hours (%d events - %s)\n", len(es),
+		duration.Format(es.Duration(), r.durFmt))
+	r.printEvents("", es)
+}
+
+// printDaily prints the total duration of each category per day. Events
+// crossing midnight are split, so that each day only counts its own part.
+// If working hours are defined, each total is checked against the working
+// time of the day and working days within the range without any events are
+// included until now.
+func (r report) printDaily(cs []cat.Category, start, end time.Time) {
+	days := []time.Time{}
+	byDay := map[time.Time]map[string]time.Duration{}
+	addDay := func(day time.Time) {
+		if _, ok := byDay[day]; !ok {
+			days = append(days, day)
+			byDay[day] = map[string]time.Duration{}
+		}
+	}
+	for _, c := range cs {
+		for _, e := range c.Events.SplitDays(time.Local) {
+			y, m, d := e.StartTime().In(time.Local).Date()
+			day := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+			addDay(day)
+			byDay[day][c.Name] += e.Duration()
+		}
+	}
+	if !r.sched.Empty() {
+		y, m, d := start.In(time.Local).Date()
+		now := time.Now()
+		for day := time.Date(y, m, d, 0, 0, 0, 0, time.Local); day.Before(end) && day.Before(now); day = day.AddDate(0, 0, 1) {
+			if r.sched.Target(day) > 0 {
+				addDay(day)
+			}
+		}
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Before(days[j])
+	})
+
+	fmt.Println(strings.Repeat("=", 80))
+	for _, day := range days {
+		var total time.Duration
+		for _, d := range byDay[day] {
+			total += d
+		}
+		if r.sched.Empty() {
+			fmt.Printf("%s (%s)", day.Format(r.dateFmt), duration.Format(total, r.durFmt))
+		} else {
+			target := r.sched.Target(day)
+			fmt.Printf("%s (%s of %s)", day.Format(r.dateFmt),
+				duration.Format(total, r.durFmt), duration.Format(target, r.durFmt))
+			if total < target {
+				fmt.Printf(" <- %s below target", duration.Format(target-total, r.durFmt))
+			}
+		}
+		if n, ok := r.sched.Holidays.Get(day); ok {
+			fmt.Printf(" <- holiday: %s", n)
+		}
+		fmt.Println()
+		for _, c := range cs {
+			if d, ok := byDay[day][c.Name]; ok {
+				fmt.Printf("  %s (%s)\n", c.Name, duration.Format(d, r.durFmt))
+			}
+		}
+	}
+}
+
+// printNode prints the node at the given level (starting with 1) with its
+// booking code and billable amount, followed by its own events and its
+// children.
+func (r report) printNode(n *cat.Node, level int) {
+	indent := strings.Repeat("  ", level-1)
+	all := n.AllEvents()
+	fmt.Printf("%s%s", indent, n.Name)
+	if n.Metadata.BookingCode != "" {
+		fmt.Printf(" [%s]", n.Metadata.BookingCode)
+	}
+	fmt.Printf(" (%d events - %s", len(all), duration.Format(all.Duration(), r.durFmt))
+	if a := n.Amount(); a > 0 {
+		fmt.Printf(", billable: %.2f", a)
+	} else if n.Metadata.Billable {
+		fmt.Printf(", billable")
+	}
+	fmt.Print(")")
+	if n.Uncategorized {
+		fmt.Print(" <- no mapping rule matched")
+	}
+	fmt.Println()
+
+	if r.depth > 0 && level >= r.depth {
+		r.printEvents(indent, all)
+		return
+	}
+	r.printEvents(indent, n.Events)
+	for _, c := range n.Children {
+		r.printNode(c, level+1)
+	}
+}
+
+// printEvents prints the events with the given indentation.
+func (r report) printEvents(indent string, es event.Events) {
+	for _, e := range es {
+		fmt.Printf("%s%v %s (%s", indent,
+			e.StartTime().Format(r.timeFmt), e.Summary(),
+			duration.Format(e.Duration(), r.durFmt))
+		if sh, ok := e.(*cat.Share); ok {
+			fmt.Printf(" = %.0f%% of %s", 100*sh.Fraction,
+				duration.Format(sh.Wrapper.Duration(), r.durFmt))
+		}
+		if d := event.Original(e).Duration(); d != scheduled(e).Duration() {
+			fmt.Printf(" of %s scheduled", duration.Format(d, r.durFmt))
+		}
+		fmt.Print(")")
+		if g, ok := e.(*cat.Guess); ok {
+			fmt.Printf(" <- guessed with %.0f%% confidence", 100*g.Confidence)
+		}
+		fmt.Println()
+		if x := r.mp.Explain(event.Original(e)); r.explain && x.Match != nil {
+			fmt.Printf("%s    matched by %s\n", indent, formatRule(*x.Match))
+		}
+	}
+}
+
+// scheduled returns the event without categorization details, i.e., the time
+// attributed to it after clipping and resolving overlaps.
+func scheduled(e event.Wrapper) event.Wrapper {
+	for {
+		switch w := e.(type) {
+		case *cat.Guess:
+			e = w.Wrapper
+		case *cat.Share:
+			e = w.Wrapper
+		default:
+			return e
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/abc-inc/cal2cat/cat"
+	"github.com/abc-inc/cal2cat/event"
+	"github.com/abc-inc/cal2cat/internal/duration"
+	"github.com/adrg/xdg"
+	"github.com/spf13/cobra"
+)
+
+// defSuggestStart is the default start of the time range for suggestions.
+const defSuggestStart = "-3cm"
+
+func newSuggestCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "suggest",
+		Short: "Suggest mapping rules for uncategorized events.",
+		Long: `suggest clusters the summaries of all uncategorized events by common
+prefixes, ticket keys and shared tokens, and prints a small set of rules in
+the syntax of the mapping section, which covers all of them.
+The rules are ordered by the total duration of the events they cover.
+
+Unless specified otherwise, the time range starts ` + defSuggestStart + `.`,
+		Run: suggest,
+	}
+}
+
+func suggest(cmd *cobra.Command, args []string) {
+	cfgPath, _ := xdg.ConfigFile("cal2booking/config.ini")
+	cfg := readConfig(cfgPath)
+	durFmt := cfg.Section("settings").Key("durationFormat").MustString(defDurFmt)
+
+	if !cmd.Flags().Changed("start") {
+		_ = cmd.Flags().Set("start", defSuggestStart)
+	}
+	rangeStart, rangeEnd := timeRange(cmd)
+
+	mp := newMapping(cfg, cfgPath, loadHistory(cfg))
+	es := event.Events{}
+	for _, c := range mp.Map(resolveConflicts(cmd, cfg, mp, loadEvents(cmd, cfg, mp, rangeStart, rangeEnd))) {
+		if c.Uncategorized {
+			es = c.Events
+		}
+	}
+
+	ss, err := cat.Suggest(es, mp.Normalizer)
+	if err != nil {
+		log.Fatalf("cannot suggest rules: %v", err)
+	}
+
+	fmt.Println("[mapping]")
+	for _, s := range ss {
+		fmt.Printf("; %d events - %s: %s\n", len(s.Events),
+			duration.Format(s.Events.Duration(), durFmt), examples(s.Events, 3))
+		l, err := iniLine(s.Pattern, s.Category)
+		if err != nil {
+			l = "; " + err.Error()
+		}
+		fmt.Println(l)
+	}
+}
+
+// examples returns up to n distinct summaries of the events.
+func examples(es event.Events, n int) string {
+	seen := map[string]interface{}{}
+	ss := []string{}
+	for _, e := range es {
+		if _, ok := seen[e.Summary()]; ok {
+			continue
+		} else if len(ss) == n {
+			ss = append(ss, "...")
+			break
+		}
+		seen[e.Summary()] = nil
+		ss = append(ss, fmt.Sprintf("%q", e.Summary()))
+	}
+	return strings.Join(ss, ", ")
+}
@@ -0,0 +1,159 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/cal2cat/cal"
+	"github.com/abc-inc/cal2cat/cat"
+	"github.com/abc-inc/cal2cat/event"
+	"github.com/abc-inc/cal2cat/internal/duration"
+	"github.com/abc-inc/cal2cat/work"
+	"github.com/spf13/cobra"
+	"gopkg.in/ini.v1"
+)
+
+// gapSummary is the summary of events representing unaccounted working time.
+const gapSummary = "(unaccounted)"
+
+// newSchedule creates the working hours from the section workingHours, where
+// each key is a list of weekdays like "mon-fri" and the value is a list of
+// shifts like "08:00-12:00,13:00-17:00". Later keys override earlier ones.
+// The key breaks contains shifts excluded from every day.
+//
+// The key holidays contains a comma-separated list of regions with built-in
+// public holidays (e.g., "AT") and calendars (path or URL), whose events mark
+// non-working days within the given range.
+func newSchedule(cfg *ini.File, start, end time.Time) work.Schedule {
+	s := work.Schedule{Days: map[time.Weekday][]work.Shift{}, Holidays: work.Holidays{}}
+	for _, k := range cfg.Section("workingHours").Keys() {
+		switch k.Name() {
+		case "fill", "clip", "overtime", "convertAllDay":
+			continue
+		case "holidays":
+			s.Holidays = newHolidays(k.Strings(","), start, end)
+			continue
+		}
+
+		shs, err := work.ParseShifts(k.Value())
+		if err != nil {
+			log.Fatalf("invalid working hours %s=%s: %v", k.Name(), k.Value(), err)
+		}
+		if k.Name() == "breaks" {
+			s.Breaks = shs
+			continue
+		}
+
+		days, err := cat.ParseWeekdays(k.Name())
+		if err != nil {
+			log.Fatalf("invalid working hours %s=%s: %v", k.Name(), k.Value(), err)
+		}
+		for _, d := range days {
+			s.Days[d] = shs
+		}
+	}
+	return s
+}
+
+// newHolidays computes the built-in holidays of the regions and loads the
+// holidays from calendars for the years of the given range.
+func newHolidays(srcs []string, start, end time.Time) work.Holidays {
+	years := []int{}
+	for y := start.Year(); y <= end.Year(); y++ {
+		years = append(years, y)
+	}
+
+	h := work.Holidays{}
+	for _, src := range srcs {
+		if isCalendar(src) {
+			es := cal.Load(cal.Source{Name: "holidays", Path: src})
+			h.Merge(work.HolidaysFromEvents(es, time.Local))
+			continue
+		}
+		rh, err := work.NewHolidays(src, years...)
+		if err != nil {
+			log.Fatalf("invalid holidays %s: no such calendar file and %v", src, err)
+		}
+		h.Merge(rh)
+	}
+	return h
+}
+
+// isCalendar checks whether the source is a URL or an existing file.
+func isCalendar(src string) bool {
+	if strings.Contains(src, "://") {
+		return true
+	}
+	_, err := os.Stat(src)
+	return err == nil
+}
+
+// gaps returns the working time within the range, which is not covered by
+// any event, as events. The range ends now at the latest.
+func gaps(s work.Schedule, es event.Events, start, end time.Time) event.Events {
+	if now := time.Now(); end.After(now) {
+		end = now
+	}
+	gs := event.Events{}
+	for _, iv := range s.Gaps(es, start.In(time.Local), end) {
+		gs = append(gs, event.NewSimpleEvent(iv.Start, iv.End, gapSummary))
+	}
+	return gs
+}
+
+// addEvents adds the events to the named category, e.g., gaps or time outside
+// the working hours. The category is created, if it does not exist yet.
+func addEvents(mp cat.Mapping, cs []cat.Category, name string, add event.Events) []cat.Category {
+	i := sort.Search(len(cs), func(i int) bool {
+		return cs[i].Uncategorized || cs[i].Name >= name
+	})
+	if i == len(cs) || cs[i].Name != name {
+		cs = append(cs[:i], append([]cat.Category{{Name: name, Metadata: mp.Metadata[name]}}, cs[i:]...)...)
+	}
+
+	es := append(cs[i].Events, add...)
+	sort.SliceStable(es, func(i, j int) bool {
+		return es[i].StartTime().Before(es[j].StartTime())
+	})
+	cs[i].Events = es
+	return cs
+}
+
+// clipToHours returns whether events are clipped to the working hours as given
+// by the flag clip-hours or the setting clip in the section workingHours.
+func clipToHours(cmd *cobra.Command, cfg *ini.File) bool {
+	if cmd.Flags().Changed("clip-hours") {
+		clip, _ := cmd.Flags().GetBool("clip-hours")
+		return clip
+	}
+	return cfg.Section("workingHours").Key("clip").MustBool(false)
+}
+
+// printGaps lists the unaccounted working time.
+func printGaps(gs event.Events, timeFmt, durFmt string) {
+	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("Unaccounted working time (%d gaps - %s)\n", len(gs),
+		duration.Format(gs.Duration(), durFmt))
+	for _, g := range gs {
+		fmt.Printf("%v - %v (%s)\n", g.StartTime().Format(timeFmt),
+			g.EndTime().Format(timeFmt), duration.Format(g.Duration(), durFmt))
+	}
+}
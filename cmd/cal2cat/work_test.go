@@ -0,0 +1,32 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestIsCalendar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holidays")
+	NoError(t, os.WriteFile(path, []byte("BEGIN:VCALENDAR\nEND:VCALENDAR\n"), 0o600))
+	True(t, isCalendar(path))
+	True(t, isCalendar("https://example.com/holidays.ics"))
+	False(t, isCalendar("AT"))
+	False(t, isCalendar("DE-BY"))
+}
@@ -26,16 +26,20 @@ type Wrapper interface {
 	EndTime() time.Time
 	Duration() time.Duration
 	Summary() string
+	// Calendar returns the name of the calendar the event belongs to.
+	Calendar() string
 }
 
 // CalEvent represents an iCalendar event.
 type CalEvent struct {
-	event ical.Event
+	event    ical.Event
+	calendar string
 }
 
-// NewCalEvent creates a new iCalendar event.
-func NewCalEvent(e ical.Event) *CalEvent {
-	return &CalEvent{e}
+// NewCalEvent creates a new iCalendar event, which belongs to the named
+// calendar.
+func NewCalEvent(e ical.Event, calendar string) *CalEvent {
+	return &CalEvent{e, calendar}
 }
 
 // StartTime returns the start time in the system's local time zone.
@@ -60,6 +64,11 @@ func (a CalEvent) Summary() string {
 	return a.event.Props.Get(ical.PropSummary).Value
 }
 
+// Calendar returns the name of the calendar the event was loaded from.
+func (a CalEvent) Calendar() string {
+	return a.calendar
+}
+
 // SimpleEvent represents an event with minimal set of properties.
 type SimpleEvent struct {
 	startTime time.Time
@@ -92,6 +101,29 @@ func (e SimpleEvent) Summary() string {
 	return e.summary
 }
 
+// Calendar returns an empty string, because custom events do not belong to
+// any calendar.
+func (e SimpleEvent) Calendar() string {
+	return ""
+}
+
+// Unwrapper is implemented by events, which decorate another event, e.g., to
+// attach additional information or to represent only a part of it.
+type Unwrapper interface {
+	Unwrap() Wrapper
+}
+
+// Original returns the innermost event by unwrapping it repeatedly.
+func Original(e Wrapper) Wrapper {
+	for {
+		u, ok := e.(Unwrapper)
+		if !ok {
+			return e
+		}
+		e = u.Unwrap()
+	}
+}
+
 // Filter checks whether an event matches a certain criteria.
 type Filter func(e Wrapper) bool
 
@@ -117,11 +149,20 @@ func (es Events) Filter(f Filter) Events {
 	return sub
 }
 
-// NewRangeFilter returns a new Filter, which checks whether an event
-// begins within the given range.
+// NewRangeFilter returns a new Filter, which checks whether an event lies
+// within the given range. Events may start at start and end at end.
 func NewRangeFilter(start, end time.Time) Filter {
 	return func(e Wrapper) bool {
-		return e.StartTime().After(start) && e.EndTime().Before(end)
+		return !e.StartTime().Before(start) && !e.EndTime().After(end)
+	}
+}
+
+// NewOverlapFilter returns a new Filter, which checks whether an event shares
+// any time with the given range.
+func NewOverlapFilter(start, end time.Time) Filter {
+	r := Interval{start, end}
+	return func(e Wrapper) bool {
+		return r.Overlaps(Span(e))
 	}
 }
 
@@ -139,6 +180,39 @@ func NewEventFilter(set Events) Filter {
 	}
 }
 
+// NewDurationFilter returns a new Filter, which checks whether the duration of
+// an event is at least shortest and at most longest.
+func NewDurationFilter(shortest, longest time.Duration) Filter {
+	return func(e Wrapper) bool {
+		return e.Duration() >= shortest && e.Duration() <= longest
+	}
+}
+
+// NewSummaryFilter returns a new Filter, which checks whether the summary of
+// an event matches any of the given functions.
+func NewSummaryFilter(matches ...func(string) bool) Filter {
+	return func(e Wrapper) bool {
+		for _, m := range matches {
+			if m(e.Summary()) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Any returns a new Filter, which checks whether any of the filters matches.
+func Any(fs ...Filter) Filter {
+	return func(e Wrapper) bool {
+		for _, f := range fs {
+			if f(e) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 // Not negates the given Filter.
 func (f Filter) Not() Filter {
 	return func(e Wrapper) bool {
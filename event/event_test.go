@@ -0,0 +1,58 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/abc-inc/cal2cat/event"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestNewRangeFilter(t *testing.T) {
+	a, b, c := events()
+	r := Span(b)
+
+	f := NewRangeFilter(r.Start, r.End)
+	False(t, f(a))
+	True(t, f(b))
+	True(t, f(c))
+
+	f = NewOverlapFilter(Span(c).Start, Span(c).End)
+	False(t, f(a))
+	True(t, f(b))
+	True(t, f(c))
+}
+
+func TestNewDurationFilter(t *testing.T) {
+	a, b, c := events()
+	f := NewDurationFilter(time.Hour, 2*time.Hour)
+	True(t, f(a))
+	False(t, f(b))
+	True(t, f(c))
+}
+
+func TestNewSummaryFilter(t *testing.T) {
+	a, b, c := events()
+	f := NewSummaryFilter(func(s string) bool { return s == "A" }, func(s string) bool { return s == "C" })
+	Equal(t, Events{a, c}, Events{a, b, c}.Filter(f))
+	Equal(t, Events{b}, Events{a, b, c}.Filter(f.Not()))
+	False(t, NewSummaryFilter()(a))
+
+	g := Any(NewSummaryFilter(func(s string) bool { return s == "A" }), NewDurationFilter(3*time.Hour, 3*time.Hour))
+	Equal(t, Events{a, b}, Events{a, b, c}.Filter(g))
+	False(t, Any()(a))
+}
@@ -0,0 +1,264 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+import (
+	"sort"
+	"time"
+)
+
+// Interval is the half-open time span [Start, End).
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Span returns the interval of an event.
+func Span(e Wrapper) Interval {
+	return Interval{e.StartTime(), e.EndTime()}
+}
+
+// Duration returns the length of the interval.
+func (i Interval) Duration() time.Duration {
+	return i.End.Sub(i.Start)
+}
+
+// Empty checks whether the interval does not contain any point in time.
+func (i Interval) Empty() bool {
+	return !i.Start.Before(i.End)
+}
+
+// Overlaps checks whether both intervals share any point in time.
+func (i Interval) Overlaps(j Interval) bool {
+	return i.Start.Before(j.End) && j.Start.Before(i.End)
+}
+
+// Part is the part of an event within an interval, e.g., the reporting period.
+type Part struct {
+	Wrapper
+	Interval
+}
+
+// StartTime returns the start of the part.
+func (p Part) StartTime() time.Time {
+	return p.Start
+}
+
+// EndTime returns the end of the part.
+func (p Part) EndTime() time.Time {
+	return p.End
+}
+
+// Duration returns the length of the part.
+func (p Part) Duration() time.Duration {
+	return p.Interval.Duration()
+}
+
+// Unwrap returns the event.
+func (p Part) Unwrap() Wrapper {
+	return p.Wrapper
+}
+
+// IntervalSet is a set of points in time represented by disjoint, non-adjacent
+// and non-empty intervals ordered by start time.
+type IntervalSet []Interval
+
+// NewIntervalSet creates a new IntervalSet from arbitrary intervals by merging
+// overlapping and adjacent ones. Empty intervals are ignored.
+func NewIntervalSet(is ...Interval) IntervalSet {
+	ss := make([]Interval, 0, len(is))
+	for _, i := range is {
+		if !i.Empty() {
+			ss = append(ss, i)
+		}
+	}
+	sort.Slice(ss, func(i, j int) bool {
+		return ss[i].Start.Before(ss[j].Start)
+	})
+
+	s := IntervalSet{}
+	for _, i := range ss {
+		if n := len(s); n > 0 && !i.Start.After(s[n-1].End) {
+			if i.End.After(s[n-1].End) {
+				s[n-1].End = i.End
+			}
+			continue
+		}
+		s = append(s, i)
+	}
+	return s
+}
+
+// Duration returns the total length of all intervals.
+func (s IntervalSet) Duration() (d time.Duration) {
+	for _, i := range s {
+		d += i.Duration()
+	}
+	return
+}
+
+// Contains checks whether the point in time is in the set.
+func (s IntervalSet) Contains(t time.Time) bool {
+	n := sort.Search(len(s), func(n int) bool {
+		return s[n].End.After(t)
+	})
+	return n < len(s) && !t.Before(s[n].Start)
+}
+
+// Union returns all points in time contained in either set.
+func (s IntervalSet) Union(t IntervalSet) IntervalSet {
+	return NewIntervalSet(append(append([]Interval{}, s...), t...)...)
+}
+
+// Intersect returns all points in time contained in both sets.
+func (s IntervalSet) Intersect(t IntervalSet) IntervalSet {
+	res := IntervalSet{}
+	for i, j := 0, 0; i < len(s) && j < len(t); {
+		iv := Interval{later(s[i].Start, t[j].Start), earlier(s[i].End, t[j].End)}
+		if !iv.Empty() {
+			res = append(res, iv)
+		}
+		if s[i].End.Before(t[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return res
+}
+
+// Subtract returns all points in time contained in s, but not in t.
+func (s IntervalSet) Subtract(t IntervalSet) IntervalSet {
+	res := IntervalSet{}
+	j := 0
+	for _, iv := range s {
+		for ; j < len(t) && !t[j].End.After(iv.Start); j++ {
+		}
+		for k := j; k < len(t) && t[k].Start.Before(iv.End); k++ {
+			if t[k].Start.After(iv.Start) {
+				res = append(res, Interval{iv.Start, t[k].Start})
+			}
+			iv.Start = t[k].End
+		}
+		if !iv.Empty() {
+			res = append(res, iv)
+		}
+	}
+	return res
+}
+
+// Clip returns all points in time of the set within [start, end).
+func (s IntervalSet) Clip(start, end time.Time) IntervalSet {
+	return s.Intersect(NewIntervalSet(Interval{start, end}))
+}
+
+// Clip returns the events within or sharing time with the range. Events
+// extending beyond the range are replaced by the Part within it.
+func (es Events) Clip(start, end time.Time) Events {
+	r := Interval{start, end}
+	within := NewRangeFilter(start, end)
+	res := Events{}
+	for _, e := range es {
+		switch s := Span(e); {
+		case within(e):
+			res = append(res, e)
+		case r.Overlaps(s):
+			res = append(res, &Part{e, Interval{later(s.Start, start), earlier(s.End, end)}})
+		}
+	}
+	return res
+}
+
+// SplitDays splits events crossing midnight in the given location into one
+// Part per day. Other events are retained.
+//
+// If the duration of an event differs from its span, e.g., for a Portion, it
+// is distributed proportionally to the parts, which are wrapped in a Portion.
+func (es Events) SplitDays(loc *time.Location) Events {
+	res := Events{}
+	for _, e := range es {
+		s := Span(e)
+		ivs := []Interval{}
+		for start := s.Start; ; {
+			y, m, d := start.In(loc).Date()
+			midnight := time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+			if !midnight.Before(s.End) {
+				ivs = append(ivs, Interval{start, s.End})
+				break
+			}
+			ivs = append(ivs, Interval{start, midnight})
+			start = midnight
+		}
+
+		if len(ivs) == 1 {
+			res = append(res, e)
+		} else {
+			res = append(res, parts(e, ivs)...)
+		}
+	}
+	return res
+}
+
+// parts divides the event into one Part per interval. If the duration of the
+// event differs from its span, each Part is wrapped in a Portion with its
+// proportional share and the last one gets the remainder.
+func parts(e Wrapper, ivs []Interval) Events {
+	ps := make(Events, len(ivs))
+	d, span := e.Duration(), Span(e).Duration()
+	left := d
+	for i, iv := range ivs {
+		ps[i] = &Part{e, iv}
+		if d == span {
+			continue
+		}
+		l := left
+		if i < len(ivs)-1 {
+			l = time.Duration(float64(d) * float64(iv.Duration()) / float64(span))
+		}
+		ps[i], left = &Portion{ps[i], l}, left-l
+	}
+	return ps
+}
+
+// Intervals returns the time covered by the events.
+func (es Events) Intervals() IntervalSet {
+	is := make([]Interval, len(es))
+	for i, e := range es {
+		is[i] = Span(e)
+	}
+	return NewIntervalSet(is...)
+}
+
+// BusyTime returns the time covered by at least one event, i.e., overlapping
+// time is counted once.
+func (es Events) BusyTime() time.Duration {
+	return es.Intervals().Duration()
+}
+
+// earlier returns the earlier of two points in time.
+func earlier(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// later returns the later of two points in time.
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,120 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/abc-inc/cal2cat/event"
+	. "github.com/stretchr/testify/require"
+)
+
+// hours creates intervals from pairs of hours today.
+func hours(hs ...int) IntervalSet {
+	today := time.Now().Truncate(24 * time.Hour)
+	s := IntervalSet{}
+	for i := 0; i+1 < len(hs); i += 2 {
+		s = append(s, Interval{
+			Start: today.Add(time.Duration(hs[i]) * time.Hour),
+			End:   today.Add(time.Duration(hs[i+1]) * time.Hour),
+		})
+	}
+	return s
+}
+
+func TestNewIntervalSet(t *testing.T) {
+	Equal(t, hours(7, 11, 12, 13), NewIntervalSet(hours(9, 10, 12, 13, 7, 9, 8, 11, 14, 14)...))
+	Equal(t, 5*time.Hour, NewIntervalSet(hours(9, 10, 12, 13, 7, 9, 8, 11)...).Duration())
+	Empty(t, NewIntervalSet())
+}
+
+func TestIntervalSet_Algebra(t *testing.T) {
+	s := hours(8, 12, 13, 17)
+	u := hours(7, 9, 10, 11, 16, 18)
+
+	Equal(t, hours(7, 12, 13, 18), s.Union(u))
+	Equal(t, hours(8, 9, 10, 11, 16, 17), s.Intersect(u))
+	Equal(t, hours(9, 10, 11, 12, 13, 16), s.Subtract(u))
+	Equal(t, hours(12, 13), hours(7, 18).Subtract(s.Union(u)))
+	Equal(t, s, s.Subtract(IntervalSet{}))
+	Equal(t, hours(10, 12, 13, 14), s.Clip(hours(10, 14)[0].Start, hours(10, 14)[0].End))
+
+	True(t, s.Contains(hours(8, 9)[0].Start))
+	False(t, s.Contains(hours(12, 13)[0].Start))
+	True(t, s.Contains(hours(13, 14)[0].Start))
+}
+
+func TestEvents_BusyTime(t *testing.T) {
+	a, b, c := events()
+	es := Events{a, b, c}
+	Equal(t, 6*time.Hour, es.Duration())
+	Equal(t, 4*time.Hour, es.BusyTime())
+	Equal(t, hours(8, 12), es.Intervals())
+	True(t, Span(a).Overlaps(Span(b)))
+	False(t, Span(a).Overlaps(Span(c)))
+}
+
+func TestEvents_Clip(t *testing.T) {
+	a, b, c := events()
+	r := hours(9, 11)[0]
+
+	es := Events{a, b, c}.Clip(r.Start, r.End)
+	Equal(t, 2, len(es))
+	Equal(t, hours(9, 10)[0], Span(es[0]))
+	Equal(t, time.Hour, es[0].Duration())
+	Equal(t, a, Original(es[0]))
+	Equal(t, hours(9, 11)[0], Span(es[1]))
+	Equal(t, b, es[1].(*Part).Wrapper)
+
+	es = Events{a, b, c}.Clip(Span(b).Start, Span(b).End)
+	Equal(t, b, es[1])
+	Equal(t, c, es[2])
+}
+
+func TestEvents_SplitDays(t *testing.T) {
+	loc := time.FixedZone("CEST", 2*60*60)
+	start := time.Date(2021, 5, 24, 22, 0, 0, 0, loc)
+	a := NewSimpleEvent(start, start.Add(4*time.Hour), "A")
+	b := NewSimpleEvent(start.Add(-2*time.Hour), start.Add(2*time.Hour), "B")
+	c := NewSimpleEvent(start, start.Add(50*time.Hour), "C")
+
+	es := Events{a, b, c}.SplitDays(loc)
+	Equal(t, 6, len(es))
+	Equal(t, 2*time.Hour, es[0].Duration())
+	Equal(t, 2*time.Hour, es[1].Duration())
+	Equal(t, time.Date(2021, 5, 25, 0, 0, 0, 0, loc), es[1].StartTime())
+	Equal(t, a, Original(es[1]))
+	Equal(t, b, es[2])
+	Equal(t, Events{c, c, c}, Events{Original(es[3]), Original(es[4]), Original(es[5])})
+	Equal(t, 24*time.Hour, es[4].Duration())
+	Equal(t, 50*time.Hour, es[3:].Duration())
+
+	// 22:00-02:00 CEST is 20:00-00:00 UTC
+	Equal(t, 1, len(Events{a}.SplitDays(time.UTC)))
+}
+
+func TestEvents_SplitDays_Portion(t *testing.T) {
+	loc := time.FixedZone("CEST", 2*60*60)
+	start := time.Date(2021, 5, 24, 23, 0, 0, 0, loc)
+	a := NewSimpleEvent(start, start.Add(3*time.Hour), "A")
+
+	es := Events{&Portion{a, 90 * time.Minute}}.SplitDays(loc)
+	Equal(t, 2, len(es))
+	Equal(t, 30*time.Minute, es[0].Duration())
+	Equal(t, time.Date(2021, 5, 25, 0, 0, 0, 0, loc), es[1].StartTime())
+	Equal(t, time.Hour, es[1].Duration())
+	Equal(t, a, Original(es[1]))
+}
@@ -14,13 +14,23 @@
 
 package event
 
+import (
+	"sort"
+	"time"
+)
+
 // present is the value indicating that a key is present in a map.
 var present interface{}
 
 // Conflict represents overlapping events.
 type Conflict struct {
-	Event  Wrapper
+	// Event is the event, which is dropped.
+	Event Wrapper
+	// Reason is the overlapping event, which is kept instead.
 	Reason Wrapper
+	// Causes are all events overlapping Event ordered by start time,
+	// including Reason.
+	Causes Events
 }
 
 // Conflicts represents multiple overlapping events.
@@ -35,37 +45,98 @@ func (cs Conflicts) Events() Events {
 	return es
 }
 
-// Conflicts returns all conflicting events with their first overlapping event.
-func (es Events) Conflicts() (cs Conflicts) {
-	ignored := map[Wrapper]interface{}{}
-	for i, j := 0, 1; i < len(es); j++ {
-		if j >= len(es) {
-			// if the second index runs out of range, proceed with the next one
-			i, j = i+1, i+1
-			continue
+// Conflicts returns all conflicting events ordered by start time, if the
+// earliest events are kept (see Keep).
+func (es Events) Conflicts() Conflicts {
+	_, cs := es.Keep(Earliest)
+	return cs
+}
+
+// Clusters returns groups of transitively overlapping events ordered by start
+// time. Events not overlapping any other event are omitted.
+func (es Events) Clusters() []Events {
+	cls := []Events{}
+	var cl Events
+	var end time.Time
+	for _, e := range es.sorted() {
+		if len(cl) > 0 && e.StartTime().Before(end) {
+			cl = append(cl, e)
+		} else {
+			if len(cl) > 1 {
+				cls = append(cls, cl)
+			}
+			cl = Events{e}
+			end = e.EndTime()
+		}
+		if e.EndTime().After(end) {
+			end = e.EndTime()
 		}
+	}
+	if len(cl) > 1 {
+		cls = append(cls, cl)
+	}
+	return cls
+}
 
-		cur := es[i]
-		next := es[j]
+// Overlap returns the total time covered by at least two events.
+func (es Events) Overlap() (d time.Duration) {
+	type boundary struct {
+		t     time.Time
+		delta int
+	}
+	bs := make([]boundary, 0, 2*len(es))
+	for _, e := range es {
+		bs = append(bs, boundary{e.StartTime(), 1}, boundary{e.EndTime(), -1})
+	}
+	sort.Slice(bs, func(i, j int) bool {
+		return bs[i].t.Before(bs[j].t)
+	})
 
-		_, ignore := ignored[cur]
-		switch {
-		case ignore:
-			// if the current event is a known conflict, proceed with the next one
-			i, j = i+1, i+1
-		case !overlaps(cur, next):
-			// if the current event does not overlap with the next one, proceed
-			i, j = i+1, i+1
-		default:
-			// conflict detect: record the event and the cause
-			ignored[next] = present
-			cs = append(cs, Conflict{next, cur})
+	active := 0
+	for i, b := range bs {
+		if active >= 2 {
+			d += b.t.Sub(bs[i-1].t)
 		}
+		active += b.delta
 	}
 	return
 }
 
+// sorted returns a copy of the events ordered by start time. Events starting
+// at the same time retain their order.
+func (es Events) sorted() Events {
+	ss := append(Events{}, es...)
+	sort.SliceStable(ss, func(i, j int) bool {
+		return ss[i].StartTime().Before(ss[j].StartTime())
+	})
+	return ss
+}
+
+// overlapping returns all overlapping events of each event in sorted order.
+// Events, which have not ended yet, are kept in an active list while sweeping.
+// Each check either finds an overlap or removes an event from the list, so the
+// effort is proportional to the number of events and overlaps.
+func overlapping(ss Events) map[Wrapper]Events {
+	ovs := map[Wrapper]Events{}
+	active := Events{}
+	for _, e := range ss {
+		n := 0
+		for _, a := range active {
+			if a.EndTime().After(e.StartTime()) {
+				ovs[a] = append(ovs[a], e)
+				ovs[e] = append(ovs[e], a)
+				active[n] = a
+				n++
+			}
+		}
+		active = append(active[:n], e)
+	}
+	return ovs
+}
+
 // overlaps checks if the second event starts before the first one ends.
+// The first event must not start after the second one. Hence, an empty event
+// overlaps an event starting at the same time, but only if it comes second.
 func overlaps(fst, snd Wrapper) bool {
 	return snd.StartTime().Before(fst.EndTime())
 }
@@ -57,3 +57,70 @@ func TestEvents_Conflicts(t *testing.T) {
 	Equal(t, "A", es[0].Summary())
 	Equal(t, "D", es[1].Summary())
 }
+
+// TestEvents_Conflicts_LongEvent tests that overlaps with a long event are
+// detected even if the events in between do not overlap.
+//
+//	07:00 A
+//	08:00 A B
+//	09:00 A   C
+//	10:00 A     D
+//	11:00         E
+func TestEvents_Conflicts_LongEvent(t *testing.T) {
+	today := time.Now().Truncate(24 * time.Hour)
+	a := NewSimpleEvent(today.Add(7*time.Hour), today.Add(11*time.Hour), "A")
+	b := NewSimpleEvent(today.Add(8*time.Hour), today.Add(9*time.Hour), "B")
+	c := NewSimpleEvent(today.Add(9*time.Hour), today.Add(10*time.Hour), "C")
+	d := NewSimpleEvent(today.Add(10*time.Hour), today.Add(11*time.Hour), "D")
+	e := NewSimpleEvent(today.Add(11*time.Hour), today.Add(12*time.Hour), "E")
+
+	cs := Events{e, d, c, b, a}.Conflicts()
+	Equal(t, Events{b, c, d}, cs.Events())
+	for _, c := range cs {
+		Equal(t, a, c.Reason)
+		Equal(t, Events{a}, c.Causes)
+	}
+
+	cls := Events{a, b, c, d, e}.Clusters()
+	Equal(t, []Events{{a, b, c, d}}, cls)
+}
+
+func TestEvents_Conflicts_Causes(t *testing.T) {
+	today := time.Now().Truncate(24 * time.Hour)
+	a := NewSimpleEvent(today.Add(7*time.Hour), today.Add(9*time.Hour), "A")
+	b := NewSimpleEvent(today.Add(8*time.Hour), today.Add(11*time.Hour), "B")
+	c := NewSimpleEvent(today.Add(8*time.Hour), today.Add(9*time.Hour), "C")
+	d := NewSimpleEvent(today.Add(9*time.Hour), today.Add(10*time.Hour), "D")
+	e := NewSimpleEvent(today.Add(12*time.Hour), today.Add(13*time.Hour), "E")
+	f := NewSimpleEvent(today.Add(12*time.Hour), today.Add(13*time.Hour), "F")
+
+	cs := Events{a, b, c, d, e, f}.Conflicts()
+	Equal(t, 3, len(cs))
+	Equal(t, Events{a, c, d}, cs[0].Causes)
+	Equal(t, Events{a, b}, cs[1].Causes)
+	Equal(t, e, cs[2].Reason)
+	Equal(t, Events{e}, cs[2].Causes)
+
+	Equal(t, []Events{{a, b, c, d}, {e, f}}, Events{a, b, c, d, e, f}.Clusters())
+	Empty(t, Events{a, e}.Clusters())
+
+	Equal(t, 3*time.Hour, Events{a, b, c, d, e, f}.Overlap())
+	Equal(t, time.Duration(0), Events{a, e}.Overlap())
+}
+
+// TestEvents_Conflicts_Empty tests that an empty event conflicts with an event
+// starting at the same time, only if it comes second.
+func TestEvents_Conflicts_Empty(t *testing.T) {
+	today := time.Now().Truncate(24 * time.Hour)
+	a := NewSimpleEvent(today.Add(8*time.Hour), today.Add(9*time.Hour), "A")
+	z := NewSimpleEvent(today.Add(8*time.Hour), today.Add(8*time.Hour), "Z")
+
+	cs := Events{a, z}.Conflicts()
+	Equal(t, Events{z}, cs.Events())
+	Equal(t, a, cs[0].Reason)
+
+	Empty(t, Events{z, a}.Conflicts())
+	kept, cs := Events{z, a}.Keep(Longest)
+	Equal(t, Events{z, a}, kept)
+	Empty(t, cs)
+}
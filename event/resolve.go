@@ -0,0 +1,186 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+import (
+	"sort"
+	"time"
+)
+
+// Preference reports whether event a is preferred over event b when they
+// overlap.
+type Preference func(a, b Wrapper) bool
+
+// Portion is the part of an event's time, which is attributed to it after
+// resolving overlaps with other events.
+type Portion struct {
+	Wrapper
+	Length time.Duration
+}
+
+// Duration returns the attributed time instead of the duration of the event.
+func (p Portion) Duration() time.Duration {
+	return p.Length
+}
+
+// Unwrap returns the event.
+func (p Portion) Unwrap() Wrapper {
+	return p.Wrapper
+}
+
+// Earliest prefers events starting earlier.
+func Earliest(a, b Wrapper) bool {
+	return a.StartTime().Before(b.StartTime())
+}
+
+// Longest prefers longer events. Events of equal duration are preferred by
+// their start time.
+func Longest(a, b Wrapper) bool {
+	if a.Duration() != b.Duration() {
+		return a.Duration() > b.Duration()
+	}
+	return Earliest(a, b)
+}
+
+// ByPriority prefers events with higher priority. The priority functions are
+// consulted in the given order until one of them differs. Events of equal
+// priority are preferred by their start time.
+func ByPriority(prios ...func(Wrapper) int) Preference {
+	return func(a, b Wrapper) bool {
+		for _, p := range prios {
+			if pa, pb := p(a), p(b); pa != pb {
+				return pa > pb
+			}
+		}
+		return Earliest(a, b)
+	}
+}
+
+// Keep keeps the most preferred events, which do not overlap each other, and
+// returns them ordered by start time together with the conflicts of all other
+// events. The Reason of a conflict is the kept event it overlaps.
+func (es Events) Keep(pref Preference) (Events, Conflicts) {
+	ss := es.sorted()
+	causes := overlapping(ss)
+
+	byPref := append(Events{}, ss...)
+	sort.SliceStable(byPref, func(i, j int) bool {
+		return pref(byPref[i], byPref[j])
+	})
+
+	// kept events are ordered by start time and do not overlap, so only the
+	// neighbors of an event can overlap it. Events starting at the same time
+	// are ordered like ss, because overlaps depends on their order.
+	pos := make(map[Wrapper]int, len(ss))
+	for i, e := range ss {
+		pos[e] = i
+	}
+	kept := Events{}
+	reasons := map[Wrapper]Wrapper{}
+	for _, e := range byPref {
+		i := sort.Search(len(kept), func(i int) bool {
+			return pos[kept[i]] > pos[e]
+		})
+		switch {
+		case i > 0 && overlaps(kept[i-1], e):
+			reasons[e] = kept[i-1]
+		case i < len(kept) && overlaps(e, kept[i]):
+			reasons[e] = kept[i]
+		default:
+			kept = append(kept[:i], append(Events{e}, kept[i:]...)...)
+		}
+	}
+
+	cs := Conflicts{}
+	for _, e := range ss {
+		if r, ok := reasons[e]; ok {
+			cs = append(cs, Conflict{e, r, causes[e]})
+		}
+	}
+	return kept, cs
+}
+
+// Split divides overlapping time equally between the events. Events sharing
+// time with others are wrapped in a Portion.
+func (es Events) Split() Events {
+	return es.distribute(func(seg time.Duration, active Events, ds map[Wrapper]time.Duration) {
+		for _, a := range active {
+			ds[a] += seg / time.Duration(len(active))
+		}
+	})
+}
+
+// Busy attributes overlapping time to the most preferred event only, so that
+// it is counted once. Events losing part of their time are wrapped in a
+// Portion, events losing all of their time are omitted.
+func (es Events) Busy(pref Preference) Events {
+	return es.distribute(func(seg time.Duration, active Events, ds map[Wrapper]time.Duration) {
+		best := active[0]
+		for _, a := range active[1:] {
+			if pref(a, best) {
+				best = a
+			}
+		}
+		ds[best] += seg
+	})
+}
+
+// distribute sweeps the segments between consecutive start and end times and
+// lets assign attribute each segment to the events active during it.
+// It returns the events ordered by start time with their attributed time.
+func (es Events) distribute(assign func(seg time.Duration, active Events, ds map[Wrapper]time.Duration)) Events {
+	ss := es.sorted()
+	ts := make([]time.Time, 0, 2*len(ss))
+	for _, e := range ss {
+		ts = append(ts, e.StartTime(), e.EndTime())
+	}
+	sort.Slice(ts, func(i, j int) bool {
+		return ts[i].Before(ts[j])
+	})
+
+	ds := map[Wrapper]time.Duration{}
+	active := Events{}
+	next := 0
+	for i := 0; i+1 < len(ts); i++ {
+		from, to := ts[i], ts[i+1]
+		if !from.Before(to) {
+			continue
+		}
+		for ; next < len(ss) && !ss[next].StartTime().After(from); next++ {
+			active = append(active, ss[next])
+		}
+		n := 0
+		for _, a := range active {
+			if a.EndTime().After(from) {
+				active[n] = a
+				n++
+			}
+		}
+		if active = active[:n]; len(active) > 0 {
+			assign(to.Sub(from), active, ds)
+		}
+	}
+
+	res := Events{}
+	for _, e := range ss {
+		switch d := ds[e]; {
+		case d == e.Duration():
+			res = append(res, e)
+		case d > 0:
+			res = append(res, &Portion{e, d})
+		}
+	}
+	return res
+}
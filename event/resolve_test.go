@@ -0,0 +1,91 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/abc-inc/cal2cat/event"
+	. "github.com/stretchr/testify/require"
+)
+
+// events creates the following events:
+//
+//	08:00 A
+//	09:00 A B
+//	10:00   B
+//	11:00   B C
+//	12:00
+func events() (a, b, c Wrapper) {
+	today := time.Now().Truncate(24 * time.Hour)
+	a = NewSimpleEvent(today.Add(8*time.Hour), today.Add(10*time.Hour), "A")
+	b = NewSimpleEvent(today.Add(9*time.Hour), today.Add(12*time.Hour), "B")
+	c = NewSimpleEvent(today.Add(11*time.Hour), today.Add(12*time.Hour), "C")
+	return
+}
+
+func TestEvents_Keep(t *testing.T) {
+	a, b, c := events()
+	es := Events{a, b, c}
+
+	kept, cs := es.Keep(Earliest)
+	Equal(t, Events{a, c}, kept)
+	Equal(t, Events{b}, cs.Events())
+	Equal(t, a, cs[0].Reason)
+	Equal(t, Events{a, c}, cs[0].Causes)
+
+	kept, cs = es.Keep(Longest)
+	Equal(t, Events{b}, kept)
+	Equal(t, Events{a, c}, cs.Events())
+	Equal(t, b, cs[1].Reason)
+
+	prio := func(e Wrapper) int {
+		if e.Summary() == "C" {
+			return 1
+		}
+		return 0
+	}
+	kept, _ = es.Keep(ByPriority(prio))
+	Equal(t, Events{a, c}, kept)
+	kept, _ = es.Keep(ByPriority(func(Wrapper) int { return 0 }, prio))
+	Equal(t, Events{a, c}, kept)
+}
+
+func TestEvents_Split(t *testing.T) {
+	a, b, c := events()
+	es := Events{c, b, a}.Split()
+	Equal(t, 3, len(es))
+	Equal(t, 90*time.Minute, es[0].Duration())
+	Equal(t, 2*time.Hour, es[1].Duration())
+	Equal(t, 30*time.Minute, es[2].Duration())
+	Equal(t, a, Original(es[0]))
+	Equal(t, 4*time.Hour, es.Duration())
+
+	Equal(t, Events{a}, Events{a}.Split())
+}
+
+func TestEvents_Busy(t *testing.T) {
+	a, b, c := events()
+	es := Events{a, b, c}.Busy(Earliest)
+	Equal(t, 2, len(es))
+	Equal(t, a, es[0])
+	Equal(t, b, Original(es[1]))
+	Equal(t, 2*time.Hour, es[1].Duration())
+
+	es = Events{a, b, c}.Busy(Longest)
+	Equal(t, Events{b}, es[1:])
+	Equal(t, time.Hour, es[0].Duration())
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package history stores previously categorized events locally.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Entry is a categorized event.
+type Entry struct {
+	Start    time.Time `json:"start"`
+	Summary  string    `json:"summary"`
+	Category string    `json:"category"`
+}
+
+// History is a set of categorized events, which is stored in a JSON file.
+// Each event is identified by its start time and summary, i.e., recording the
+// same event again replaces its previous category.
+type History struct {
+	path    string
+	entries map[string]Entry
+}
+
+// Load reads the history from the given file. A missing file is treated as
+// empty history.
+func Load(path string) (*History, error) {
+	h := &History{path, map[string]Entry{}}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	es := []Entry{}
+	if err := json.Unmarshal(b, &es); err != nil {
+		return nil, err
+	}
+	for _, e := range es {
+		h.Add(e)
+	}
+	return h, nil
+}
+
+// Add records a categorized event.
+func (h *History) Add(e Entry) {
+	h.entries[e.Start.UTC().Format(time.RFC3339)+"\x00"+e.Summary] = e
+}
+
+// Entries returns all entries ordered by start time.
+func (h *History) Entries() []Entry {
+	es := make([]Entry, 0, len(h.entries))
+	for _, e := range h.entries {
+		es = append(es, e)
+	}
+	sort.SliceStable(es, func(i, j int) bool {
+		if !es[i].Start.Equal(es[j].Start) {
+			return es[i].Start.Before(es[j].Start)
+		}
+		return es[i].Summary < es[j].Summary
+	})
+	return es
+}
+
+// Save writes the history to its file.
+func (h *History) Save() error {
+	b, err := json.MarshalIndent(h.Entries(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(h.path, b, 0o600)
+}
@@ -0,0 +1,46 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/abc-inc/cal2cat/internal/history"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "history.json")
+	h, err := Load(path)
+	NoError(t, err)
+	Empty(t, h.Entries())
+
+	start := time.Date(2021, 5, 24, 8, 30, 0, 0, time.UTC)
+	h.Add(Entry{start.Add(time.Hour), "B", "Cat B"})
+	h.Add(Entry{start, "A", "Cat A"})
+	h.Add(Entry{start, "A", "Cat C"})
+	NoError(t, h.Save())
+
+	h, err = Load(path)
+	NoError(t, err)
+	es := h.Entries()
+	Equal(t, 2, len(es))
+	Equal(t, "A", es[0].Summary)
+	Equal(t, "Cat C", es[0].Category)
+	Equal(t, "B", es[1].Summary)
+	True(t, start.Add(time.Hour).Equal(es[1].Start))
+}
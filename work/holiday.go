@@ -0,0 +1,178 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package work
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/cal2cat/event"
+)
+
+// dateFmt is the layout of the days in Holidays.
+const dateFmt = "2006-01-02"
+
+// Holidays maps non-working days (formatted as "2006-01-02") to their names.
+type Holidays map[string]string
+
+// rule defines a holiday either by a fixed date or relative to Easter Sunday.
+type rule struct {
+	name   string
+	month  time.Month
+	day    int
+	easter bool
+}
+
+// fixed creates a rule for a holiday on the same date every year.
+func fixed(name string, month time.Month, day int) rule {
+	return rule{name: name, month: month, day: day}
+}
+
+// easter creates a rule for a holiday with an offset in days to Easter Sunday.
+func easter(name string, offset int) rule {
+	return rule{name: name, day: offset, easter: true}
+}
+
+// date returns the date of the holiday in the given year.
+func (r rule) date(year int) time.Time {
+	if r.easter {
+		return Easter(year).AddDate(0, 0, r.day)
+	}
+	return time.Date(year, r.month, r.day, 0, 0, 0, 0, time.UTC)
+}
+
+var (
+	holidaysDE = []rule{
+		fixed("New Year's Day", time.January, 1),
+		easter("Good Friday", -2),
+		easter("Easter Monday", 1),
+		fixed("Labour Day", time.May, 1),
+		easter("Ascension Day", 39),
+		easter("Whit Monday", 50),
+		fixed("German Unity Day", time.October, 3),
+		fixed("Christmas Day", time.December, 25),
+		fixed("St. Stephen's Day", time.December, 26),
+	}
+
+	// regions contains the public holidays of countries and regions.
+	regions = map[string][]rule{
+		"AT": {
+			fixed("New Year's Day", time.January, 1),
+			fixed("Epiphany", time.January, 6),
+			easter("Easter Monday", 1),
+			fixed("Labour Day", time.May, 1),
+			easter("Ascension Day", 39),
+			easter("Whit Monday", 50),
+			easter("Corpus Christi", 60),
+			fixed("Assumption Day", time.August, 15),
+			fixed("National Day", time.October, 26),
+			fixed("All Saints' Day", time.November, 1),
+			fixed("Immaculate Conception", time.December, 8),
+			fixed("Christmas Day", time.December, 25),
+			fixed("St. Stephen's Day", time.December, 26),
+		},
+		"DE": holidaysDE,
+		"DE-BY": append([]rule{
+			fixed("Epiphany", time.January, 6),
+			easter("Corpus Christi", 60),
+			fixed("Assumption Day", time.August, 15),
+			fixed("All Saints' Day", time.November, 1),
+		}, holidaysDE...),
+		"CH": {
+			fixed("New Year's Day", time.January, 1),
+			easter("Good Friday", -2),
+			easter("Easter Monday", 1),
+			easter("Ascension Day", 39),
+			easter("Whit Monday", 50),
+			fixed("Swiss National Day", time.August, 1),
+			fixed("Christmas Day", time.December, 25),
+			fixed("St. Stephen's Day", time.December, 26),
+		},
+	}
+)
+
+// Regions returns the sorted codes of all countries and regions with built-in
+// holidays.
+func Regions() []string {
+	rs := make([]string, 0, len(regions))
+	for r := range regions {
+		rs = append(rs, r)
+	}
+	sort.Strings(rs)
+	return rs
+}
+
+// NewHolidays computes the public holidays of a country or region (e.g., "AT"
+// or "DE-BY") for the given years.
+func NewHolidays(region string, years ...int) (Holidays, error) {
+	rs, ok := regions[strings.ToUpper(region)]
+	if !ok {
+		return nil, fmt.Errorf("unknown region %q, expected any of %s", region, strings.Join(Regions(), ", "))
+	}
+
+	h := Holidays{}
+	for _, y := range years {
+		for _, r := range rs {
+			h[r.date(y).Format(dateFmt)] = r.name
+		}
+	}
+	return h, nil
+}
+
+// HolidaysFromEvents creates Holidays from events like all-day events of a
+// holiday calendar. Each day covered by an event (in the given location) is a
+// holiday.
+func HolidaysFromEvents(es event.Events, loc *time.Location) Holidays {
+	h := Holidays{}
+	for _, e := range es.SplitDays(loc) {
+		if e.Duration() > 0 {
+			h[e.StartTime().In(loc).Format(dateFmt)] = e.Summary()
+		}
+	}
+	return h
+}
+
+// Get returns the name of the holiday on the day of t (in its location) and
+// whether it is a holiday.
+func (h Holidays) Get(t time.Time) (string, bool) {
+	n, ok := h[t.Format(dateFmt)]
+	return n, ok
+}
+
+// Merge adds all holidays of o.
+func (h Holidays) Merge(o Holidays) {
+	for d, n := range o {
+		h[d] = n
+	}
+}
+
+// Easter returns Easter Sunday of the given year in the Gregorian calendar
+// (anonymous Gregorian algorithm).
+func Easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,66 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package work_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abc-inc/cal2cat/event"
+	. "github.com/abc-inc/cal2cat/work"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestEaster(t *testing.T) {
+	for _, d := range []string{"2000-04-23", "2019-04-21", "2021-04-04", "2024-03-31", "2038-04-25"} {
+		e, _ := time.Parse("2006-01-02", d)
+		Equal(t, e, Easter(e.Year()))
+	}
+}
+
+func TestNewHolidays(t *testing.T) {
+	h, err := NewHolidays("at", 2021, 2022)
+	NoError(t, err)
+	for d, n := range map[string]string{
+		"2021-05-13": "Ascension Day", "2021-05-24": "Whit Monday",
+		"2021-06-03": "Corpus Christi", "2022-04-18": "Easter Monday",
+		"2022-12-08": "Immaculate Conception",
+	} {
+		Equal(t, n, h[d], d)
+	}
+	_, ok := h.Get(at(25, 10, 0))
+	False(t, ok)
+
+	h, err = NewHolidays("DE-BY", 2021)
+	NoError(t, err)
+	Equal(t, "Good Friday", h["2021-04-02"])
+	Equal(t, "Epiphany", h["2021-01-06"])
+
+	_, err = NewHolidays("XY", 2021)
+	Error(t, err)
+	Contains(t, Regions(), "AT")
+}
+
+func TestHolidaysFromEvents(t *testing.T) {
+	h := HolidaysFromEvents(event.Events{
+		event.NewSimpleEvent(at(24, 0, 0), at(26, 0, 0), "Whit Monday and Tuesday"),
+		event.NewSimpleEvent(at(28, 0, 0), at(28, 0, 0), "Reminder"),
+	}, at(24, 0, 0).Location())
+	Equal(t, Holidays{"2021-05-24": "Whit Monday and Tuesday", "2021-05-25": "Whit Monday and Tuesday"}, h)
+
+	s := newSchedule(t)
+	s.Holidays = h
+	Equal(t, 2*(8*time.Hour+30*time.Minute)+4*time.Hour, s.Hours(at(24, 0, 0), at(31, 0, 0)).Duration())
+}
@@ -0,0 +1,184 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package work
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/cal2cat/cat"
+	"github.com/abc-inc/cal2cat/event"
+)
+
+// Shift is a span of working time within a day given as offsets from midnight.
+type Shift struct {
+	From time.Duration
+	To   time.Duration
+}
+
+// Schedule defines the working hours of each weekday.
+type Schedule struct {
+	// Days maps weekdays to their working hours. Weekdays without any shifts
+	// are non-working days.
+	Days map[time.Weekday][]Shift
+	// Breaks are excluded from the working hours of every day.
+	Breaks []Shift
+	// Holidays are non-working days (optional).
+	Holidays Holidays
+}
+
+// Empty checks whether the schedule does not contain any working hours.
+func (s Schedule) Empty() bool {
+	for _, shs := range s.Days {
+		if len(shs) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Hours returns the working hours within [start, end) in the location of
+// start. Holidays are non-working days.
+func (s Schedule) Hours(start, end time.Time) event.IntervalSet {
+	loc := start.Location()
+	y, m, d := start.Date()
+	work, breaks := []event.Interval{}, []event.Interval{}
+	for day := time.Date(y, m, d, 0, 0, 0, 0, loc); day.Before(end); day = day.AddDate(0, 0, 1) {
+		if _, ok := s.Holidays.Get(day); ok {
+			continue
+		}
+		for _, sh := range s.Days[day.Weekday()] {
+			work = append(work, sh.on(day))
+		}
+		for _, sh := range s.Breaks {
+			breaks = append(breaks, sh.on(day))
+		}
+	}
+	return event.NewIntervalSet(work...).
+		Subtract(event.NewIntervalSet(breaks...)).
+		Clip(start, end)
+}
+
+// Gaps returns the working hours within [start, end), which are not covered
+// by any event.
+func (s Schedule) Gaps(es event.Events, start, end time.Time) event.IntervalSet {
+	return s.Hours(start, end).Subtract(es.Intervals())
+}
+
+// Clip splits the events into the time within and outside the working hours.
+// Events partially within the working hours are wrapped in an event.Portion
+// in both results. Events entirely within or outside are retained as is.
+// The time of events, which are only partially attributed to them (e.g., due
+// to overlaps), is split proportionally.
+func (s Schedule) Clip(es event.Events, loc *time.Location) (in, out event.Events) {
+	in, out = event.Events{}, event.Events{}
+	if len(es) == 0 {
+		return
+	}
+
+	ivs := es.Intervals()
+	hs := s.Hours(ivs[0].Start.In(loc), ivs[len(ivs)-1].End)
+	for _, e := range es {
+		span := event.Span(e)
+		d := hs.Intersect(event.IntervalSet{span}).Duration()
+		switch {
+		case d == span.Duration():
+			in = append(in, e)
+		case d == 0:
+			out = append(out, e)
+		default:
+			d = time.Duration(float64(e.Duration()) * float64(d) / float64(span.Duration()))
+			in = append(in, &event.Portion{Wrapper: e, Length: d})
+			out = append(out, &event.Portion{Wrapper: e, Length: e.Duration() - d})
+		}
+	}
+	return
+}
+
+// Target returns the working time on the day of t (in its location), which is
+// zero on non-working days and holidays.
+func (s Schedule) Target(t time.Time) time.Duration {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	return s.Hours(day, day.AddDate(0, 0, 1)).Duration()
+}
+
+// ConvertAllDay replaces all-day events, which start and end at midnight in
+// the given location, by one event.Part per span of working hours, e.g., to
+// count a day of vacation as a working day. All-day events without any
+// working hours (like on weekends or holidays) are omitted. Other events are
+// retained as is.
+func (s Schedule) ConvertAllDay(es event.Events, loc *time.Location) event.Events {
+	res := event.Events{}
+	for _, e := range es {
+		span := event.Span(e)
+		if span.Empty() || !isMidnight(span.Start.In(loc)) || !isMidnight(span.End.In(loc)) {
+			res = append(res, e)
+			continue
+		}
+		for _, iv := range s.Hours(span.Start.In(loc), span.End) {
+			res = append(res, &event.Part{Wrapper: e, Interval: iv})
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].StartTime().Before(res[j].StartTime())
+	})
+	return res
+}
+
+// isMidnight checks whether t is at midnight in its location.
+func isMidnight(t time.Time) bool {
+	return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0
+}
+
+// on returns the interval of the shift on the given day. The time of day is
+// computed by the wall clock, so that shifts are not affected by daylight
+// saving time transitions.
+func (sh Shift) on(day time.Time) event.Interval {
+	at := func(d time.Duration) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), 0, int(d/time.Minute), 0, 0, day.Location())
+	}
+	return event.Interval{Start: at(sh.From), End: at(sh.To)}
+}
+
+// ParseShifts parses a comma-separated list of shifts like
+// "08:00-12:00,13:00-17:00".
+func ParseShifts(s string) ([]Shift, error) {
+	shs := []Shift{}
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(p, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid shift %q", p)
+		}
+		f, err := cat.ParseClock(from)
+		if err != nil {
+			return nil, err
+		}
+		t, err := cat.ParseClock(to)
+		if err != nil {
+			return nil, err
+		}
+		if t <= f {
+			return nil, fmt.Errorf("invalid shift %q: end must be after start", p)
+		}
+		shs = append(shs, Shift{f, t})
+	}
+	return shs, nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2021 The cal2cat authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package work_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abc-inc/cal2cat/event"
+	. "github.com/abc-inc/cal2cat/work"
+	. "github.com/stretchr/testify/require"
+)
+
+// at returns the time on 2021-05-<day> (Monday is the 24th) in CET.
+func at(day, hour, min int) time.Time {
+	loc, _ := time.LoadLocation("Europe/Vienna")
+	return time.Date(2021, 5, day, hour, min, 0, 0, loc)
+}
+
+func newSchedule(t *testing.T) Schedule {
+	full, err := ParseShifts("08:00-17:00")
+	NoError(t, err)
+	half, err := ParseShifts("08:00-12:00")
+	NoError(t, err)
+	breaks, err := ParseShifts("12:00-12:30")
+	NoError(t, err)
+	return Schedule{
+		Days: map[time.Weekday][]Shift{
+			time.Monday: full, time.Tuesday: full, time.Wednesday: full,
+			time.Thursday: full, time.Friday: half,
+		},
+		Breaks: breaks,
+	}
+}
+
+func TestParseShifts(t *testing.T) {
+	shs, err := ParseShifts("08:00-12:00, 13:00-17:30")
+	NoError(t, err)
+	Equal(t, []Shift{{8 * time.Hour, 12 * time.Hour}, {13 * time.Hour, 17*time.Hour + 30*time.Minute}}, shs)
+
+	for _, s := range []string{"08:00", "8-12", "12:00-08:00", "08:00-25:00", "20:00-24:30"} {
+		_, err = ParseShifts(s)
+		Error(t, err, s)
+	}
+}
+
+func TestSchedule_Hours(t *testing.T) {
+	s := newSchedule(t)
+	False(t, s.Empty())
+	True(t, Schedule{}.Empty())
+
+	hs := s.Hours(at(24, 0, 0), at(31, 0, 0))
+	Equal(t, 4*(8*time.Hour+30*time.Minute)+4*time.Hour, hs.Duration())
+	Equal(t, event.Interval{Start: at(24, 8, 0), End: at(24, 12, 0)}, hs[0])
+	Equal(t, event.Interval{Start: at(24, 12, 30), End: at(24, 17, 0)}, hs[1])
+
+	hs = s.Hours(at(28, 10, 0), at(30, 0, 0))
+	Equal(t, event.IntervalSet{{Start: at(28, 10, 0), End: at(28, 12, 0)}}, hs)
+}
+
+func TestSchedule_Gaps(t *testing.T) {
+	s := newSchedule(t)
+	es := event.Events{
+		event.NewSimpleEvent(at(24, 7, 0), at(24, 9, 0), "A"),
+		event.NewSimpleEvent(at(24, 11, 0), at(24, 13, 0), "B"),
+	}
+	gs := s.Gaps(es, at(24, 0, 0), at(25, 0, 0))
+	Equal(t, event.IntervalSet{
+		{Start: at(24, 9, 0), End: at(24, 11, 0)},
+		{Start: at(24, 13, 0), End: at(24, 17, 0)},
+	}, gs)
+}
+
+func TestSchedule_Clip(t *testing.T) {
+	s := newSchedule(t)
+	a := event.NewSimpleEvent(at(24, 7, 0), at(24, 9, 0), "A")
+	b := event.NewSimpleEvent(at(24, 9, 0), at(24, 12, 0), "B")
+	c := event.NewSimpleEvent(at(24, 11, 0), at(24, 13, 0), "C")
+	d := event.NewSimpleEvent(at(24, 19, 0), at(24, 22, 0), "D")
+
+	in, out := s.Clip(event.Events{a, b, c, d}, at(24, 0, 0).Location())
+	Equal(t, 3, len(in))
+	Equal(t, time.Hour, in[0].Duration())
+	Equal(t, b, in[1])
+	Equal(t, 90*time.Minute, in[2].Duration())
+	Equal(t, event.Events{a, c, d}, event.Events{event.Original(out[0]), event.Original(out[1]), out[2]})
+	Equal(t, time.Hour, out[0].Duration())
+	Equal(t, 30*time.Minute, out[1].Duration())
+
+	in, out = s.Clip(event.Events{&event.Portion{Wrapper: a, Length: time.Hour}}, at(24, 0, 0).Location())
+	Equal(t, 30*time.Minute, in.Duration())
+	Equal(t, 30*time.Minute, out.Duration())
+
+	in, out = s.Clip(event.Events{}, time.Local)
+	Empty(t, in)
+	Empty(t, out)
+}
+
+func TestSchedule_Target(t *testing.T) {
+	s := newSchedule(t)
+	s.Holidays = Holidays{"2021-05-24": "Whit Monday"}
+	Equal(t, time.Duration(0), s.Target(at(24, 10, 0)))
+	Equal(t, 8*time.Hour+30*time.Minute, s.Target(at(25, 0, 0)))
+	Equal(t, 4*time.Hour, s.Target(at(28, 23, 59)))
+	Equal(t, time.Duration(0), s.Target(at(29, 12, 0)))
+}
+
+func TestSchedule_ConvertAllDay(t *testing.T) {
+	s := newSchedule(t)
+	s.Holidays = Holidays{"2021-05-24": "Whit Monday"}
+	vacation := event.NewSimpleEvent(at(24, 0, 0), at(26, 0, 0), "Vacation")
+	weekend := event.NewSimpleEvent(at(29, 0, 0), at(31, 0, 0), "Weekend")
+	meeting := event.NewSimpleEvent(at(25, 9, 0), at(25, 10, 0), "Meeting")
+
+	es := s.ConvertAllDay(event.Events{vacation, meeting, weekend}, at(24, 0, 0).Location())
+	Equal(t, 3, len(es))
+	Equal(t, event.Interval{Start: at(25, 8, 0), End: at(25, 12, 0)}, event.Span(es[0]))
+	Equal(t, meeting, es[1])
+	Equal(t, event.Interval{Start: at(25, 12, 30), End: at(25, 17, 0)}, event.Span(es[2]))
+	Equal(t, vacation, event.Original(es[0]))
+	Equal(t, vacation, event.Original(es[2]))
+	Equal(t, s.Target(at(25, 0, 0)), es.Duration()-meeting.Duration())
+}